@@ -0,0 +1,167 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"radas/internal/frontend/apidiff"
+	"radas/internal/frontend/generator"
+	"radas/internal/frontend/parser"
+	"radas/internal/frontend/source"
+)
+
+var (
+	apiDiffBase     string
+	apiDiffContract string
+	apiDiffFormat   string
+	apiDiffOffline  bool
+)
+
+func init() {
+	apiDiffCmd.Flags().StringVar(&apiDiffBase, "base", "", "Compare the spec against its version at this git ref, e.g. origin/main")
+	apiDiffCmd.Flags().StringVar(&apiDiffContract, "contract", "", "Compare the named API contract from radas.yml")
+	apiDiffCmd.Flags().StringVarP(&apiDiffFormat, "format", "f", "text", "Report format: "+strings.Join(apidiff.Formats, ", "))
+	apiDiffCmd.Flags().BoolVar(&apiDiffOffline, "offline", false, "Use cached copies of remote specs without fetching")
+}
+
+var apiDiffCmd = &cobra.Command{
+	Use:   "api-diff [old] [new]",
+	Short: "Report breaking changes between two OpenAPI specs",
+	Long: `Compare two versions of an OpenAPI contract and classify each change as breaking
+or non-breaking for existing clients: removed operations, new required parameters,
+narrowed enums and removed response fields are breaking.
+
+  radas fe api-diff old.yaml new.yaml
+  radas fe api-diff --base origin/main            # contract from radas.yml
+  radas fe api-diff --base v1.2.0 api/openapi.yaml --format markdown
+
+Exits with status 1 when there are breaking changes, for CI gating.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if apiDiffBase != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var oldPath, newPath string
+		if apiDiffBase != "" {
+			specPath := ""
+			if len(args) == 1 {
+				specPath = args[0]
+			} else {
+				contract, err := contractFromConfig(apiDiffContract)
+				if err != nil {
+					return err
+				}
+				specPath = contract.Path
+			}
+			if source.IsRemote(specPath) {
+				return fmt.Errorf("--base needs a spec in this repository, %s is remote", specPath)
+			}
+			dir, err := os.MkdirTemp("", "radas-api-diff-")
+			if err != nil {
+				return fmt.Errorf("failed to create temp dir: %w", err)
+			}
+			defer os.RemoveAll(dir)
+			if oldPath, err = specAtRef(specPath, apiDiffBase, dir); err != nil {
+				return err
+			}
+			newPath = specPath
+		} else {
+			if apiDiffContract != "" {
+				return fmt.Errorf("--contract selects a spec from radas.yml and only applies with --base")
+			}
+			for i, location := range args {
+				resolved, err := source.Resolve(location, source.Options{Offline: apiDiffOffline})
+				if err != nil {
+					return err
+				}
+				args[i] = resolved.Path
+			}
+			oldPath, newPath = args[0], args[1]
+		}
+
+		// Validation issues are the linter's business, here they would only
+		// clutter the report
+		oldSpec, err := parser.ParseOpenAPI(oldPath, parser.OpenAPIOptions{SkipValidation: true})
+		if err != nil {
+			return fmt.Errorf("failed to parse old spec: %w", err)
+		}
+		newSpec, err := parser.ParseOpenAPI(newPath, parser.OpenAPIOptions{SkipValidation: true})
+		if err != nil {
+			return fmt.Errorf("failed to parse new spec: %w", err)
+		}
+
+		report := apidiff.Compare(oldSpec, newSpec)
+		if err := report.Write(os.Stdout, apiDiffFormat); err != nil {
+			return err
+		}
+		if n := report.BreakingCount(); n > 0 {
+			// The error goes to stderr, keeping the report on stdout clean
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d breaking API change(s)", n)
+		}
+		return nil
+	},
+}
+
+// specAtRef writes the spec as it was at ref into dir and returns its path.
+// The files it refers to are copied along so relative $refs still resolve.
+func specAtRef(specPath, ref, dir string) (string, error) {
+	absSpec, err := filepath.Abs(specPath)
+	if err != nil {
+		return "", err
+	}
+	specDir := filepath.Dir(absSpec)
+	top, err := git(specDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("--base needs the spec to be in a git repository: %w", err)
+	}
+	root := strings.TrimSpace(string(top))
+	if resolvedDir, err := filepath.EvalSymlinks(specDir); err == nil {
+		absSpec = filepath.Join(resolvedDir, filepath.Base(absSpec))
+	}
+
+	// Every file the walk reads at ref is written to the same place in dir
+	read := func(filePath string) ([]byte, error) {
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside the repository", filePath)
+		}
+		content, err := git(root, "show", ref+":"+filepath.ToSlash(rel))
+		if err != nil {
+			return nil, err
+		}
+		target := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		return content, os.WriteFile(target, content, 0644)
+	}
+	if _, err := generator.ReadSpecFiles(absSpec, read); err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %w", specPath, ref, err)
+	}
+
+	rel, err := filepath.Rel(root, absSpec)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,50 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpecAtRef(t *testing.T) {
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{
+		"openapi.yaml":       "$ref: './schemas/pet.yaml#/Pet'\n",
+		"schemas/pet.yaml":   "Pet: {type: string}\n",
+		"src/main.go":        "package main\n",
+		"schemas/notes.yaml": "notes: []\n",
+	})
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=radas", "-c", "user.email=radas@example.com", "commit", "-q", "-m", "spec"},
+	} {
+		if _, err := git(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The working tree moved on since the commit
+	writeFiles(t, repo, map[string]string{"schemas/pet.yaml": "Pet: {type: integer}\n"})
+
+	dir := t.TempDir()
+	oldPath, err := specAtRef(filepath.Join(repo, "openapi.yaml"), "HEAD", dir)
+	if err != nil {
+		t.Fatalf("specAtRef() error = %v", err)
+	}
+	if oldPath != filepath.Join(dir, "openapi.yaml") {
+		t.Errorf("specAtRef() = %s, want the spec inside %s", oldPath, dir)
+	}
+	if content, err := os.ReadFile(filepath.Join(dir, "schemas", "pet.yaml")); err != nil || string(content) != "Pet: {type: string}\n" {
+		t.Errorf("the referenced file should be copied as it was at HEAD, got %q, error = %v", content, err)
+	}
+	// Only the spec and the files it refers to are copied
+	for _, name := range []string{"src/main.go", "schemas/notes.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); !os.IsNotExist(err) {
+			t.Errorf("%s should not be copied, stat error = %v", name, err)
+		}
+	}
+
+	if _, err := specAtRef(filepath.Join(repo, "missing.yaml"), "HEAD", t.TempDir()); err == nil {
+		t.Errorf("a spec missing at the ref should fail")
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+	"radas/internal/frontend/source"
 )
 
 // RadasConfig represents the structure of radas.yml
@@ -16,17 +18,137 @@ type RadasConfig struct {
 	Type        string   `yaml:"type"`
 	Stacks      []string `yaml:"stacks"`
 	Contract    struct {
-		Design []struct {
-			Path string `yaml:"path"`
-			Type string `yaml:"type"`
-		} `yaml:"design"`
-		API []struct {
-			Path string `yaml:"path"`
-			Type string `yaml:"type"`
-		} `yaml:"api"`
+		Design []DesignContract `yaml:"design"`
+		API    []APIContract    `yaml:"api"`
 	} `yaml:"contract"`
 }
 
+// DesignContract is a directory of design tokens styles are generated from
+type DesignContract struct {
+	Path string `yaml:"path"`
+	Type string `yaml:"type"`
+}
+
+// APIContract describes one OpenAPI contract and how its client is generated
+type APIContract struct {
+	Name       string   `yaml:"name"`
+	Path       string   `yaml:"path"`
+	Type       string   `yaml:"type"`
+	Output     string   `yaml:"output"`
+	BaseURL    string   `yaml:"base_url"`
+	Targets    []string `yaml:"targets"`
+	Templates  string   `yaml:"templates"` // directory of user targets, one folder of .tmpl files each
+	Layout     string   `yaml:"layout"`    // single (default) or namespaces
+	Validation struct {
+		Skip       *bool `yaml:"skip"`
+		ErrorsOnly *bool `yaml:"errors_only"`
+		Runtime    *bool `yaml:"runtime"` // Zod validation in fetch and axios clients
+	} `yaml:"validation"`
+	Mock struct {
+		Fixtures string `yaml:"fixtures"` // defaults to mocks/<name> next to radas.yml
+		BasePath string `yaml:"base_path"`
+		Port     int    `yaml:"port"`
+	} `yaml:"mock"`
+	Lint struct {
+		Rules map[string]string `yaml:"rules"` // rule id -> off, info, warning or error
+	} `yaml:"lint"`
+	Pagination struct {
+		CursorParams []string `yaml:"cursor_params"`
+		PageParams   []string `yaml:"page_params"`
+		OffsetParams []string `yaml:"offset_params"`
+		SizeParams   []string `yaml:"size_params"`
+		NextFields   []string `yaml:"next_fields"`
+		ItemsFields  []string `yaml:"items_fields"`
+		TotalFields  []string `yaml:"total_fields"`
+	} `yaml:"pagination"` // names that mark list operations as paginated, replacing the defaults per list
+	Merge struct {
+		Strategy string        `yaml:"strategy"` // error, first or last
+		Title    string        `yaml:"title"`
+		Sources  []MergeSource `yaml:"sources"`
+	} `yaml:"merge"` // merge-api writes the sources into path
+
+	location string // path as written in radas.yml, keys the lock file
+}
+
+// MergeSource is one service spec merged into a contract by merge-api
+type MergeSource struct {
+	Namespace  string `yaml:"namespace"`
+	Path       string `yaml:"path"`
+	PathPrefix string `yaml:"path_prefix"`
+}
+
+// contractNamePattern keeps contract names usable as folder names and query keys
+var contractNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// APIContracts returns the API contracts with their local spec path and output
+// directory resolved against baseDir. Unnamed contracts are named after their
+// spec file. Every contract is generated into __generated__/api/<name> unless
+// it sets output; a lone unnamed contract keeps the __generated__/api layout.
+// If only is not empty, just the contract with that name is returned.
+func (c *RadasConfig) APIContracts(baseDir, only string) ([]APIContract, error) {
+	legacy := len(c.Contract.API) == 1 && c.Contract.API[0].Name == ""
+
+	seen := make(map[string]bool)
+	contracts := make([]APIContract, 0, len(c.Contract.API))
+	for _, contract := range c.Contract.API {
+		if contract.Name == "" && !legacy {
+			base := filepath.Base(contract.Path)
+			contract.Name = strings.TrimSuffix(base, filepath.Ext(base))
+		}
+		if contract.Name != "" && !contractNamePattern.MatchString(contract.Name) {
+			return nil, fmt.Errorf("invalid API contract name %q: use letters, digits, '-' or '_'", contract.Name)
+		}
+		if seen[contract.Name] {
+			return nil, fmt.Errorf("duplicate API contract name %q: set a unique name for each contract", contract.Name)
+		}
+		seen[contract.Name] = true
+
+		contract.location = contract.Path
+		// Remote sources are fetched into the cache when generating
+		if !source.IsRemote(contract.Path) {
+			contract.Path = ResolvePath(baseDir, contract.Path)
+		}
+		if contract.Templates != "" {
+			contract.Templates = ResolvePath(baseDir, contract.Templates)
+		}
+		sources := make([]MergeSource, 0, len(contract.Merge.Sources))
+		for _, src := range contract.Merge.Sources {
+			if !source.IsRemote(src.Path) {
+				src.Path = ResolvePath(baseDir, src.Path)
+			}
+			sources = append(sources, src)
+		}
+		contract.Merge.Sources = sources
+		switch {
+		case contract.Mock.Fixtures != "":
+			contract.Mock.Fixtures = ResolvePath(baseDir, contract.Mock.Fixtures)
+		default:
+			contract.Mock.Fixtures = filepath.Join(baseDir, "mocks", contract.Name)
+		}
+		switch {
+		case contract.Output != "":
+			contract.Output = ResolvePath(baseDir, contract.Output)
+		case legacy:
+			contract.Output = filepath.Join(baseDir, "__generated__/api")
+		default:
+			contract.Output = filepath.Join(baseDir, "__generated__/api", contract.Name)
+		}
+		contracts = append(contracts, contract)
+	}
+
+	if only == "" {
+		return contracts, nil
+	}
+	names := make([]string, 0, len(contracts))
+	for _, contract := range contracts {
+		if contract.Name == only {
+			return []APIContract{contract}, nil
+		}
+		names = append(names, contract.Name)
+	}
+	return nil, fmt.Errorf("API contract %q not found in radas.yml (available: %s)", only, strings.Join(names, ", "))
+}
+
 // ParseConfig reads and parses the radas.yml file
 func ParseConfig(configPath string) (*RadasConfig, error) {
 	// If configPath is a directory, look for radas.yml inside it
@@ -55,6 +177,15 @@ func FindConfig() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
+	return findConfigFrom(dir)
+}
+
+// findConfigFrom looks for radas.yml in dir and its parent directories
+func findConfigFrom(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
 
 	for {
 		configPath := filepath.Join(dir, "radas.yml")
@@ -73,6 +204,35 @@ func FindConfig() (string, error) {
 	return "", fmt.Errorf("radas.yml not found in current directory or any parent directory")
 }
 
+// contractFromConfig returns the API contract of the nearest radas.yml for
+// commands working on one spec, which must be selected by name when there
+// are several
+func contractFromConfig(name string) (APIContract, error) {
+	configPath, err := FindConfig()
+	if err != nil {
+		return APIContract{}, fmt.Errorf("no spec given and %w", err)
+	}
+	cfg, err := ParseConfig(configPath)
+	if err != nil {
+		return APIContract{}, err
+	}
+	contracts, err := cfg.APIContracts(filepath.Dir(configPath), name)
+	if err != nil {
+		return APIContract{}, err
+	}
+	switch len(contracts) {
+	case 0:
+		return APIContract{}, fmt.Errorf("no API contracts in %s, pass a spec", configPath)
+	case 1:
+		return contracts[0], nil
+	}
+	names := make([]string, 0, len(contracts))
+	for _, contract := range contracts {
+		names = append(names, contract.Name)
+	}
+	return APIContract{}, fmt.Errorf("%s has several API contracts, select one with --contract (available: %s)", configPath, strings.Join(names, ", "))
+}
+
 // ResolvePath resolves a path from the configuration file
 // If the path starts with ${RADAS_PLAYGROUND}, it will be replaced with the value of the RADAS_PLAYGROUND environment variable
 // Otherwise, the path is assumed to be relative to the configuration file's directory
@@ -0,0 +1,155 @@
+package frontend
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"radas/internal/frontend/generator"
+)
+
+func TestAPIContracts(t *testing.T) {
+	t.Setenv("RADAS_PLAYGROUND", "")
+	base := filepath.FromSlash("/project")
+
+	cases := []struct {
+		name    string
+		api     []APIContract
+		only    string
+		want    []APIContract // only the name, path and output are compared
+		wantErr string
+	}{
+		{
+			name: "names default to the spec file",
+			api:  []APIContract{{Path: "specs/users.yaml"}, {Name: "billing", Path: "specs/billing.json"}},
+			want: []APIContract{
+				{Name: "users", Path: "/project/specs/users.yaml", Output: "/project/__generated__/api/users"},
+				{Name: "billing", Path: "/project/specs/billing.json", Output: "/project/__generated__/api/billing"},
+			},
+		},
+		{
+			name: "a lone unnamed contract keeps the legacy output",
+			api:  []APIContract{{Path: "openapi.yaml"}},
+			want: []APIContract{{Name: "", Path: "/project/openapi.yaml", Output: "/project/__generated__/api"}},
+		},
+		{
+			name: "output is resolved against the config",
+			api:  []APIContract{{Name: "users", Path: "users.yaml", Output: "src/api"}},
+			want: []APIContract{{Name: "users", Path: "/project/users.yaml", Output: "/project/src/api"}},
+		},
+		{
+			name: "remote paths are left to the source cache",
+			api:  []APIContract{{Name: "users", Path: "https://example.com/users.yaml"}},
+			want: []APIContract{{Name: "users", Path: "https://example.com/users.yaml", Output: "/project/__generated__/api/users"}},
+		},
+		{
+			name:    "invalid names",
+			api:     []APIContract{{Name: "1users", Path: "users.yaml"}},
+			wantErr: `invalid API contract name "1users"`,
+		},
+		{
+			name:    "names derived from the spec file are validated",
+			api:     []APIContract{{Path: "users.v1.yaml"}, {Path: "billing.yaml"}},
+			wantErr: `invalid API contract name "users.v1"`,
+		},
+		{
+			name:    "duplicate names",
+			api:     []APIContract{{Path: "a/users.yaml"}, {Path: "b/users.yaml"}},
+			wantErr: `duplicate API contract name "users"`,
+		},
+		{
+			name: "only selects one contract",
+			api:  []APIContract{{Name: "users", Path: "users.yaml"}, {Name: "billing", Path: "billing.yaml"}},
+			only: "billing",
+			want: []APIContract{{Name: "billing", Path: "/project/billing.yaml", Output: "/project/__generated__/api/billing"}},
+		},
+		{
+			name:    "only lists the available contracts when not found",
+			api:     []APIContract{{Name: "users", Path: "users.yaml"}, {Name: "billing", Path: "billing.yaml"}},
+			only:    "orders",
+			wantErr: `API contract "orders" not found in radas.yml (available: users, billing)`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cfg RadasConfig
+			cfg.Contract.API = tc.api
+			got, err := cfg.APIContracts(base, tc.only)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("APIContracts() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("APIContracts() error = %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("APIContracts() returned %d contracts, want %d", len(got), len(tc.want))
+			}
+			for i, want := range tc.want {
+				if got[i].Name != want.Name || got[i].Path != filepath.FromSlash(want.Path) || got[i].Output != filepath.FromSlash(want.Output) {
+					t.Errorf("contract %d = {%q %q %q}, want {%q %q %q}", i, got[i].Name, got[i].Path, got[i].Output, want.Name, want.Path, want.Output)
+				}
+			}
+		})
+	}
+}
+
+func TestContractOptionsBaseURL(t *testing.T) {
+	defaults := generator.APIOptions{BaseURL: "https://api.example.com"}
+	cases := []struct {
+		name     string
+		contract string
+		flag     string
+		want     string
+	}{
+		{"the flag default applies without base_url", "", "", "https://api.example.com"},
+		{"base_url wins over the default", "https://users.example.com", "", "https://users.example.com"},
+		{"an explicit flag wins over base_url", "https://users.example.com", "https://local.test", "https://local.test"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("base-url", "https://api.example.com", "")
+			opts := defaults
+			if tc.flag != "" {
+				cmd.Flags().Set("base-url", tc.flag)
+				opts.BaseURL = tc.flag
+			}
+			got := contractOptions(cmd, APIContract{BaseURL: tc.contract}, opts)
+			if got.BaseURL != tc.want {
+				t.Errorf("BaseURL = %q, want %q", got.BaseURL, tc.want)
+			}
+		})
+	}
+}
+
+func TestFlagTargets(t *testing.T) {
+	cases := []struct {
+		name                       string
+		targets                    []string
+		zodios, hooks, stores, all bool
+		want                       string
+	}{
+		{name: "nothing selected keeps the defaults", want: ""},
+		{name: "--target", targets: []string{"fetch", "vue-query"}, want: "fetch,vue-query"},
+		{name: "--hooks", hooks: true, want: "hooks"},
+		{name: "--all stands for the default targets", all: true, want: "zodios,hooks,stores"},
+		{name: "--all keeps --target", targets: []string{"fetch"}, all: true, want: "fetch,zodios,hooks,stores"},
+		{name: "targets are not repeated", targets: []string{"zodios"}, zodios: true, want: "zodios"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			genAPITargets, genAPIZodios, genAPIHooks, genAPIStores, genAPIAll = tc.targets, tc.zodios, tc.hooks, tc.stores, tc.all
+			t.Cleanup(func() {
+				genAPITargets, genAPIZodios, genAPIHooks, genAPIStores, genAPIAll = nil, false, false, false, false
+			})
+			if got := strings.Join(flagTargets(), ","); got != tc.want {
+				t.Errorf("flagTargets() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -10,12 +10,19 @@ import (
 	"github.com/AlecAivazis/survey/v2"
 )
 
+// devGen runs the contract generators in watch mode next to the dev server
+var devGen bool
+
 // DevCmd is the command to run frontend application
 var DevCmd = &cobra.Command{
 	Use:   "dev [app-name]",
 	Short: "Run frontend application",
 	Long:  `Start the frontend application in development mode. Automatically detects and uses npm, pnpm, bun, or yarn based on lock files.
-In a monorepo, you can specify an app name to run, or choose from a list if no app name is provided.`,
+In a monorepo, you can specify an app name to run, or choose from a list if no app name is provided.
+
+With --gen, the contracts of the nearest radas.yml are generated first and regenerated
+in watch mode alongside the dev server; the output of both is prefixed with [gen] and
+[dev], and Ctrl-C stops both.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
 			// App name provided, run specific app
@@ -42,6 +49,10 @@ In a monorepo, you can specify an app name to run, or choose from a list if no a
 	},
 }
 
+func init() {
+	DevCmd.Flags().BoolVar(&devGen, "gen", false, "Generate contracts first and keep regenerating them in watch mode alongside the dev server")
+}
+
 // Package JSON structure for parsing
 type PackageJSON struct {
 	Name    string            `json:"name"`
@@ -50,8 +61,13 @@ type PackageJSON struct {
 
 // Runs the development server for an app in the specified directory
 func runFrontendDev(dir string) {
-	// Use the existing StartApp function from internal/checker to run the app
-	err := checker.StartApp(dir)
+	var err error
+	if devGen {
+		err = runDevWithGen(dir)
+	} else {
+		// Use the existing StartApp function from internal/checker to run the app
+		err = checker.StartApp(dir)
+	}
 	if err != nil {
 		fmt.Printf("Failed to start frontend application: %v\n", err)
 	}
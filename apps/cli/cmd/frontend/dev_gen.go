@@ -0,0 +1,208 @@
+package frontend
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"radas/internal/checker"
+)
+
+// devStopTimeout is how long the dev server and the generators get to exit
+// after Ctrl-C before they are killed
+var devStopTimeout = 5 * time.Second
+
+var (
+	devGenPrefix = color.New(color.FgCyan).Sprint("[gen]") + " "
+	devAppPrefix = color.New(color.FgMagenta).Sprint("[dev]") + " "
+)
+
+// runDevWithGen runs the generators of the nearest radas.yml with
+// `fe gen --watch` and, once the first generation is done, the dev server of
+// appDir next to them. The output of both is prefixed line by line. Ctrl-C
+// stops both, and so does the dev server exiting.
+func runDevWithGen(appDir string) error {
+	configPath, err := findConfigFrom(appDir)
+	if err != nil {
+		return fmt.Errorf("--gen needs a radas.yml: %w", err)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate radas: %w", err)
+	}
+	devCmd, err := checker.AppCommand(appDir)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Run gen as `radas <path of gen> --watch` whatever the command is called
+	args := append(strings.Fields(genAllCmd.CommandPath())[1:], "--watch", "--config", configPath)
+	genCmd := exec.Command(exe, args...)
+	return superviseDev(ctx, &lineMux{w: os.Stdout}, genCmd, devCmd)
+}
+
+// superviseDev runs genCmd and, once it printed genWatchReady or exited,
+// devCmd, with their output prefixed on out. Both are stopped when ctx is
+// done or devCmd exits.
+func superviseDev(ctx context.Context, out *lineMux, genCmd, devCmd *exec.Cmd) error {
+	ready := make(chan struct{})
+	var readyOnce sync.Once
+	genDone, err := startPrefixed(genCmd, out, devGenPrefix, func(line string) {
+		if strings.HasPrefix(line, genWatchReady) {
+			readyOnce.Do(func() { close(ready) })
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start the generators: %w", err)
+	}
+
+	// The dev server starts on freshly generated code
+	select {
+	case <-ready:
+	case err := <-genDone:
+		out.printf(devGenPrefix, "⚠️ Generators stopped: %v\n", exitError(err))
+		genDone = nil
+	case <-ctx.Done():
+		stopProcess(genCmd, genDone)
+		return nil
+	}
+
+	devDone, err := startPrefixed(devCmd, out, devAppPrefix, nil)
+	if err != nil {
+		stopProcess(genCmd, genDone)
+		return fmt.Errorf("failed to start the dev server: %w", err)
+	}
+
+	for {
+		select {
+		case err := <-devDone:
+			stopProcess(genCmd, genDone)
+			if err != nil {
+				return fmt.Errorf("dev server exited: %w", err)
+			}
+			return nil
+		case err := <-genDone:
+			// The dev server keeps running on the code generated so far
+			out.printf(devGenPrefix, "⚠️ Generators stopped: %v\n", exitError(err))
+			genDone = nil
+		case <-ctx.Done():
+			out.write("", "\nStopping dev server and generators...\n")
+			var wg sync.WaitGroup
+			for cmd, done := range map[*exec.Cmd]<-chan error{devCmd: devDone, genCmd: genDone} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					stopProcess(cmd, done)
+				}()
+			}
+			wg.Wait()
+			return nil
+		}
+	}
+}
+
+// startPrefixed starts cmd with its output copied line by line to out behind
+// prefix, calling onLine, if set, with every line. The returned channel
+// yields the result of Wait once the process exited and its output is copied.
+func startPrefixed(cmd *exec.Cmd, out *lineMux, prefix string, onLine func(line string)) (<-chan error, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	// Ctrl-C reaches the parent only, which stops the groups in turn
+	startGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var copied sync.WaitGroup
+	for _, r := range []io.Reader{stdout, stderr} {
+		copied.Add(1)
+		go func() {
+			defer copied.Done()
+			reader := bufio.NewReader(r)
+			for {
+				line, err := reader.ReadString('\n')
+				if line != "" {
+					if !strings.HasSuffix(line, "\n") {
+						line += "\n"
+					}
+					out.write(prefix, line)
+					if onLine != nil {
+						onLine(line)
+					}
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		// Wait closes the pipes, so it must wait for the copies
+		copied.Wait()
+		done <- cmd.Wait()
+	}()
+	return done, nil
+}
+
+// stopProcess interrupts a running process and its group and kills them if
+// it has not exited within devStopTimeout; done is the channel startPrefixed
+// returned for it, nil once the process exited
+func stopProcess(cmd *exec.Cmd, done <-chan error) {
+	if done == nil {
+		return
+	}
+	if err := interruptGroup(cmd); err != nil {
+		killGroup(cmd)
+	}
+	select {
+	case <-done:
+	case <-time.After(devStopTimeout):
+		killGroup(cmd)
+		<-done
+	}
+}
+
+// exitError describes how a process ended for a warning
+func exitError(err error) string {
+	if err == nil {
+		return "exited"
+	}
+	return err.Error()
+}
+
+// lineMux writes whole lines from several processes to w without
+// interleaving them
+type lineMux struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (m *lineMux) write(prefix, line string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	io.WriteString(m.w, prefix+line)
+}
+
+func (m *lineMux) printf(prefix, format string, args ...any) {
+	m.write(prefix, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,210 @@
+//go:build !windows
+
+package frontend
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// helperCommand runs TestHelperProcess in the given mode as a stand-in for
+// the generators or the dev server
+func helperCommand(mode string, env ...string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$", "--", mode)
+	cmd.Env = append(os.Environ(), append([]string{"GO_WANT_HELPER_PROCESS=1"}, env...)...)
+	return cmd
+}
+
+// TestHelperProcess is not a test but the process helperCommand starts
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		os.Exit(2)
+	}
+
+	// Lines are written in pieces to catch output interleaved mid-line
+	printLines := func(name string) {
+		for i := range 5 {
+			fmt.Printf("%s line %d", name, i)
+			time.Sleep(2 * time.Millisecond)
+			fmt.Println(" done")
+		}
+	}
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+
+	switch args[1] {
+	case "gen":
+		printLines("gen")
+		fmt.Fprintln(os.Stderr, "gen warning")
+		os.WriteFile(os.Getenv("DEV_GEN_MARKER"), nil, 0644)
+		fmt.Println(genWatchReady + " radas.yml")
+		<-interrupted
+		fmt.Println("gen interrupted")
+	case "gen-fail":
+		fmt.Println("broken spec")
+		os.Exit(1)
+	case "gen-tree":
+		// The child is only stopped by the interrupt sent to the group
+		signal.Ignore(os.Interrupt)
+		child := helperCommand("child")
+		stdout, _ := child.StdoutPipe()
+		if err := child.Start(); err != nil {
+			os.Exit(1)
+		}
+		lines := bufio.NewScanner(stdout)
+		lines.Scan()
+		fmt.Println(lines.Text())
+		fmt.Println(genWatchReady + " radas.yml")
+		for lines.Scan() {
+			fmt.Println(lines.Text())
+		}
+		child.Wait()
+	case "child":
+		fmt.Println("child running")
+		<-interrupted
+		fmt.Println("child interrupted")
+	case "dev":
+		if _, err := os.Stat(os.Getenv("DEV_GEN_MARKER")); err == nil {
+			fmt.Println("dev started on generated code")
+		} else {
+			fmt.Println("dev started early")
+		}
+		printLines("dev")
+	case "dev-stubborn":
+		signal.Ignore(os.Interrupt)
+		fmt.Println("dev running")
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+// outputWriter collects the output and closes seen once it contains want
+type outputWriter struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	want string
+	seen chan struct{}
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.buf.Write(p)
+	if w.want != "" && strings.Contains(w.buf.String(), w.want) {
+		close(w.seen)
+		w.want = ""
+	}
+	return n, err
+}
+
+func (w *outputWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestSuperviseDev(t *testing.T) {
+	marker := "DEV_GEN_MARKER=" + filepath.Join(t.TempDir(), "generated")
+	var out outputWriter
+	err := superviseDev(context.Background(), &lineMux{w: &out}, helperCommand("gen", marker), helperCommand("dev", marker))
+	if err != nil {
+		t.Fatalf("superviseDev() error = %v", err)
+	}
+
+	want := map[string]bool{
+		devGenPrefix + "gen warning":                   true,
+		devGenPrefix + genWatchReady + " radas.yml":    true,
+		devAppPrefix + "dev started on generated code": true,
+		// The generators are stopped once the dev server exited
+		devGenPrefix + "gen interrupted": true,
+	}
+	for i := range 5 {
+		want[fmt.Sprintf("%sgen line %d done", devGenPrefix, i)] = true
+		want[fmt.Sprintf("%sdev line %d done", devAppPrefix, i)] = true
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		if !want[line] {
+			t.Errorf("unexpected line %q", line)
+		}
+		delete(want, line)
+	}
+	for line := range want {
+		t.Errorf("missing line %q", line)
+	}
+}
+
+func TestSuperviseDevGenFails(t *testing.T) {
+	var out outputWriter
+	err := superviseDev(context.Background(), &lineMux{w: &out}, helperCommand("gen-fail"), helperCommand("dev"))
+	if err != nil {
+		t.Fatalf("superviseDev() error = %v", err)
+	}
+	// The dev server still starts on the code generated before
+	output := out.String()
+	stopped := strings.Index(output, devGenPrefix+"⚠️ Generators stopped: exit status 1\n")
+	started := strings.Index(output, devAppPrefix+"dev started")
+	if !strings.Contains(output, devGenPrefix+"broken spec\n") || stopped < 0 || started < stopped {
+		t.Errorf("the dev server should start after the generators stopped:\n%s", output)
+	}
+}
+
+func TestSuperviseDevStop(t *testing.T) {
+	timeout := devStopTimeout
+	devStopTimeout = 300 * time.Millisecond
+	t.Cleanup(func() { devStopTimeout = timeout })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := &outputWriter{want: devAppPrefix + "dev running\n", seen: make(chan struct{})}
+	devCmd := helperCommand("dev-stubborn")
+	result := make(chan error, 1)
+	go func() {
+		result <- superviseDev(ctx, &lineMux{w: out}, helperCommand("gen-tree"), devCmd)
+	}()
+
+	select {
+	case <-out.seen:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the dev server did not start:\n%s", out.String())
+	}
+	start := time.Now()
+	cancel()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("superviseDev() error = %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("superviseDev() did not return after the context was done")
+	}
+
+	// The interrupt reaches the whole group of the generators
+	if output := out.String(); !strings.Contains(output, devGenPrefix+"child interrupted\n") {
+		t.Errorf("the child of the generators should be interrupted:\n%s", output)
+	}
+	// The dev server ignoring it is killed after the timeout
+	if elapsed := time.Since(start); elapsed < devStopTimeout {
+		t.Errorf("the dev server was stopped after %s, before the timeout", elapsed)
+	}
+	if status, ok := devCmd.ProcessState.Sys().(syscall.WaitStatus); !ok || status.Signal() != syscall.SIGKILL {
+		t.Errorf("the dev server should be killed, got %v", devCmd.ProcessState)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package frontend
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startGroup makes cmd lead a process group of its own, so stopping it also
+// stops what it runs, like the bundler started by npm
+func startGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptGroup interrupts every process in the group of cmd
+func interruptGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+}
+
+// killGroup kills every process in the group of cmd
+func killGroup(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,19 @@
+package frontend
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// startGroup is a no-op, Windows has no process groups to signal
+func startGroup(cmd *exec.Cmd) {}
+
+// interruptGroup fails, interrupts cannot be sent on Windows
+func interruptGroup(cmd *exec.Cmd) error {
+	return errors.New("interrupts are not supported on Windows")
+}
+
+// killGroup kills the process of cmd
+func killGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
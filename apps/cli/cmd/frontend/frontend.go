@@ -24,4 +24,8 @@ func init() {
 	Cmd.AddCommand(genAPICmd)
 	Cmd.AddCommand(genStylesCmd)
 	Cmd.AddCommand(genAllCmd)
+	Cmd.AddCommand(mockCmd)
+	Cmd.AddCommand(apiDiffCmd)
+	Cmd.AddCommand(lintAPICmd)
+	Cmd.AddCommand(mergeAPICmd)
 }
\ No newline at end of file
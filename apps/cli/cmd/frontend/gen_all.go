@@ -3,17 +3,24 @@ package frontend
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"radas/internal/frontend/generator"
+	"radas/internal/frontend/source"
 )
 
 // genConfigPath holds the path to the radas.yml configuration file
 
 var (
 	genConfigPath string
+	genCheck      bool
+	genContract   string
+	genOffline    bool
+	genForce      bool
+	genWatch      bool
 )
 
 // genAllCmd represents the command to generate everything from radas.yml
@@ -22,7 +29,19 @@ var genAllCmd = &cobra.Command{
 	Short: "Generate frontend code from radas.yml configuration",
 	Long: `Generate API clients and style variables from radas.yml configuration.
 This command reads a radas.yml file and generates all necessary code based on the configuration.
-It will process both design tokens and API specifications as defined in the contract section.`,
+It will process both design tokens and API specifications as defined in the contract section.
+
+What was generated is recorded in __generated__/.radas-manifest.json: contracts and
+tokens whose inputs did not change are skipped, only files whose content changed are
+rewritten, and generated files edited by hand are reported. Use --force to regenerate
+everything.
+
+With --watch, radas.yml, the specs, custom templates and design token directories are
+watched and only the contracts a change affects are regenerated; errors are reported
+without exiting.
+
+With --check, API clients are rendered in memory and compared against the files
+on disk instead of being written; the command exits non-zero if any are stale.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If no config path provided, try to find radas.yml in current directory
 		if genConfigPath == "" {
@@ -32,76 +51,131 @@ It will process both design tokens and API specifications as defined in the cont
 				return fmt.Errorf("failed to find radas.yml: %w", err)
 			}
 		}
+		if genWatch && genCheck {
+			return fmt.Errorf("--watch cannot be combined with --check")
+		}
 
-		// Parse the configuration file
-		cfg, err := ParseConfig(genConfigPath)
+		project, err := loadGenProject(genConfigPath, genContract)
+		if err == nil {
+			err = project.generateAll(cmd)
+		}
+		if !genWatch {
+			return err
+		}
+		// A broken spec or radas.yml is reported and fixed while watching
 		if err != nil {
-			return fmt.Errorf("failed to parse radas.yml: %w", err)
+			fmt.Printf("❌ %v\n", err)
 		}
+		return watchGen(cmd, genConfigPath, genContract)
+	},
+}
 
-		fmt.Printf("Generating code for project: %s\n", cfg.Name)
-		fmt.Printf("Description: %s\n", cfg.Description)
-		fmt.Printf("Stacks: %s\n", strings.Join(cfg.Stacks, ", "))
-
-		// Base directory for relative paths in the config
-		baseDir := filepath.Dir(genConfigPath)
-		
-		// Process design tokens if defined
-		if len(cfg.Contract.Design) > 0 {
-			for _, design := range cfg.Contract.Design {
-				sourceDir := ResolvePath(baseDir, design.Path)
-				// Ensure output is relative to radas.yml location
-				outputDir := filepath.Join(baseDir, "__generated__/styles")
-				
-				// Ensure the source directory exists
-				if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
-					fmt.Printf("Warning: Design tokens directory %s does not exist, skipping\n", sourceDir)
-					continue
-				}
-				
-				fmt.Printf("Generating styles from: %s\n", sourceDir)
-				
-				// Determine style types
-				var types []string
-				if design.Type != "" {
-					types = []string{design.Type}
-				}
-				
-				// Generate styles
-				if err := generator.GenerateStyles(sourceDir, outputDir, types); err != nil {
-					return fmt.Errorf("failed to generate styles: %w", err)
-				}
-			}
+// genProject is a radas.yml with the contracts gen generates
+type genProject struct {
+	cfg        *RadasConfig
+	configPath string
+	baseDir    string // base directory for relative paths in the config
+	designs    []DesignContract
+	contracts  []APIContract
+}
+
+// loadGenProject parses radas.yml; only narrows generation to one API
+// contract and skips the design tokens
+func loadGenProject(configPath, only string) (*genProject, error) {
+	cfg, err := ParseConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse radas.yml: %w", err)
+	}
+	baseDir := filepath.Dir(configPath)
+	contracts, err := cfg.APIContracts(baseDir, only)
+	if err != nil {
+		return nil, err
+	}
+	project := &genProject{cfg: cfg, configPath: configPath, baseDir: baseDir, contracts: contracts}
+	if only == "" {
+		project.designs = cfg.Contract.Design
+	}
+	return project, nil
+}
+
+// generateAll generates, or with --check verifies, everything in radas.yml
+func (p *genProject) generateAll(cmd *cobra.Command) error {
+	fmt.Printf("Generating code for project: %s\n", p.cfg.Name)
+	fmt.Printf("Description: %s\n", p.cfg.Description)
+	fmt.Printf("Stacks: %s\n", strings.Join(p.cfg.Stacks, ", "))
+
+	if genCheck {
+		return p.contractRun(cmd, p.contracts).run(cmd)
+	}
+	if err := p.generate(cmd, p.designs, p.contracts); err != nil {
+		return err
+	}
+	fmt.Printf("✅ All code generation completed successfully for %s\n", p.cfg.Name)
+	return nil
+}
+
+// generate generates the given design tokens and API contracts, skipping
+// those the manifest reports as unchanged
+func (p *genProject) generate(cmd *cobra.Command, designs []DesignContract, contracts []APIContract) error {
+	manifest, err := generator.ReadManifest(manifestPath(p.configPath))
+	if err != nil {
+		return err
+	}
+
+	// Process design tokens if defined
+	for _, design := range designs {
+		sourceDir := ResolvePath(p.baseDir, design.Path)
+		// Ensure output is relative to radas.yml location
+		outputDir := filepath.Join(p.baseDir, "__generated__/styles")
+
+		// Ensure the source directory exists
+		if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
+			fmt.Printf("Warning: Design tokens directory %s does not exist, skipping\n", sourceDir)
+			continue
 		}
-		
-		// Process API specs if defined
-		if len(cfg.Contract.API) > 0 {
-			for _, api := range cfg.Contract.API {
-				specPath := ResolvePath(baseDir, api.Path)
-				// Ensure output is relative to radas.yml location
-				outputDir := filepath.Join(baseDir, "__generated__/api")
-				
-				// Ensure the API spec exists
-				if _, err := os.Stat(specPath); os.IsNotExist(err) {
-					fmt.Printf("Warning: API spec %s does not exist, skipping\n", specPath)
-					continue
-				}
-				
-				fmt.Printf("Generating API client from: %s\n", specPath)
-				
-				// Generate API client
-				// Default to skipping validation for OpenAPI 3.1.0 specs
-				skipValidation := true // Skip validation by default for batch generation
-				errorsOnly := true     // Only show errors, not warnings
-				if err := generator.GenerateAPI(specPath, outputDir, "", true, skipValidation, errorsOnly); err != nil {
-					return fmt.Errorf("failed to generate API client: %w", err)
-				}
-			}
+
+		// Determine style types
+		var types []string
+		if design.Type != "" {
+			types = []string{design.Type}
 		}
-		
-		fmt.Printf("✅ All code generation completed successfully for %s\n", cfg.Name)
-		return nil
-	},
+
+		// Generate styles
+		generated, err := manifest.GenerateStyles(path.Join("styles", design.Path), sourceDir, outputDir, types, genForce)
+		if err != nil {
+			return fmt.Errorf("failed to generate styles: %w", err)
+		}
+		if generated {
+			fmt.Printf("Generated styles from: %s\n", sourceDir)
+		} else {
+			fmt.Printf("Styles from %s are up to date, skipping\n", sourceDir)
+		}
+	}
+
+	// Process API specs if defined; the run writes the manifest
+	run := p.contractRun(cmd, contracts)
+	run.manifest = manifest
+	return run.run(cmd)
+}
+
+// contractRun returns the run generating the given contracts
+func (p *genProject) contractRun(cmd *cobra.Command, contracts []APIContract) contractRun {
+	return contractRun{
+		contracts: contracts,
+		options: func(contract APIContract) generator.APIOptions {
+			// Batch generation skips validation and only reports errors
+			// unless the contract configures validation itself
+			return contractOptions(cmd, contract, generator.APIOptions{
+				Verbose:        !genCheck,
+				SkipValidation: true,
+				ErrorsOnly:     true,
+			})
+		},
+		check:    genCheck,
+		offline:  genOffline,
+		lockPath: filepath.Join(p.baseDir, source.LockFileName),
+		force:    genForce,
+	}
 }
 
 func init() {
@@ -109,6 +183,11 @@ func init() {
 	
 	// Add flags
 	genAllCmd.Flags().StringVarP(&genConfigPath, "config", "c", "", "Path to radas.yml configuration file")
+	genAllCmd.Flags().StringVar(&genContract, "contract", "", "Generate only the named API contract")
+	genAllCmd.Flags().BoolVar(&genOffline, "offline", false, "Use cached copies of remote specs without fetching")
+	genAllCmd.Flags().BoolVar(&genForce, "force", false, "Regenerate contracts and tokens even when the manifest shows they are unchanged")
+	genAllCmd.Flags().BoolVar(&genWatch, "watch", false, "Keep running and regenerate whatever a change to radas.yml, a spec or design tokens affects")
+	genAllCmd.Flags().BoolVar(&genCheck, "check", false, "Verify generated API code is up to date without writing files; exits non-zero on drift")
 }
 
 
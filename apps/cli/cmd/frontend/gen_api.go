@@ -3,11 +3,15 @@ package frontend
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"radas/internal/frontend/generator"
+	"radas/internal/frontend/parser"
+	"radas/internal/frontend/source"
 )
 
 
@@ -23,20 +27,35 @@ var (
 	genAPIStores           bool
 	genAPISkipValidation   bool
 	genAPIErrorsOnly       bool
+	genAPICheck            bool
+	genAPIContract         string
+	genAPIOffline          bool
+	genAPITargets          []string
+	genAPITemplates        string
+	genAPIRuntimeValidate  bool
+	genAPILayout           string
 )
 
 func init() {
 
-	genAPICmd.Flags().StringVarP(&genAPISpec, "spec", "s", "./merged-api.json", "Input OpenAPI specification file")
+	genAPICmd.Flags().StringVarP(&genAPISpec, "spec", "s", "./merged-api.json", "Input OpenAPI specification file or http(s)/git+ssh URL")
 	genAPICmd.Flags().StringVar(&genAPIOutput, "output", "./src/__generated__/api", "Output directory")
 	genAPICmd.Flags().StringVar(&genAPIBaseURL, "base-url", "https://api.example.com", "Base URL for API")
 	genAPICmd.Flags().BoolVar(&genAPIVerbose, "verbose", false, "Enable verbose logging")
-	genAPICmd.Flags().BoolVar(&genAPIAll, "all", true, "Generate all client types")
+	genAPICmd.Flags().BoolVar(&genAPIAll, "all", false, "Generate all client types")
+	genAPICmd.Flags().MarkDeprecated("all", "the zodios, hooks and stores targets are generated by default, select others with --target")
 	genAPICmd.Flags().BoolVar(&genAPIZodios, "zodios", false, "Generate only Zodios client")
 	genAPICmd.Flags().BoolVar(&genAPIHooks, "hooks", false, "Generate only React Query hooks")
 	genAPICmd.Flags().BoolVar(&genAPIStores, "stores", false, "Generate only Zustand stores")
+	genAPICmd.Flags().StringSliceVar(&genAPITargets, "target", nil, "Targets to generate, e.g. zodios,hooks or fetch,vue-query,pinia")
+	genAPICmd.Flags().StringVar(&genAPITemplates, "templates", "", "Directory of custom targets layered over the built-in ones")
+	genAPICmd.Flags().BoolVar(&genAPIRuntimeValidate, "runtime-validation", false, "Validate request and response bodies with Zod in fetch and axios clients")
+	genAPICmd.Flags().StringVar(&genAPILayout, "layout", "single", "Output layout: single files, or namespaces for one folder per namespace with index.ts barrels")
 	genAPICmd.Flags().BoolVar(&genAPISkipValidation, "skip-validation", false, "Skip OpenAPI validation before code generation")
 	genAPICmd.Flags().BoolVar(&genAPIErrorsOnly, "validation-errors-only", false, "Show only error level validation issues (not warnings)")
+	genAPICmd.Flags().StringVar(&genAPIContract, "contract", "", "Generate only the named API contract from radas.yml")
+	genAPICmd.Flags().BoolVar(&genAPIOffline, "offline", false, "Use cached copies of remote specs without fetching")
+	genAPICmd.Flags().BoolVar(&genAPICheck, "check", false, "Verify generated code is up to date without writing files; exits non-zero on drift")
 
 	viper.BindPFlag("frontend.gen-api.output", genAPICmd.Flags().Lookup("output"))
 	viper.BindPFlag("frontend.gen-api.base-url", genAPICmd.Flags().Lookup("base-url"))
@@ -50,17 +69,32 @@ func init() {
 var genAPICmd = &cobra.Command{
 	Use:   "gen-api",
 	Short: "Generate TypeScript client code from OpenAPI spec",
-	Long:  `Generate Zodios client, React Query hooks, and Zustand stores from OpenAPI spec`,
+	Long:  `Generate an API client, data hooks and stores from an OpenAPI spec. Built-in targets: zodios, fetch, axios, hooks (React Query), vue-query, stores (Zustand), pinia and svelte; custom targets are loaded from --templates or the contract's templates directory`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		outputDir := viper.GetString("frontend.gen-api.output")
 		baseURL := viper.GetString("frontend.gen-api.base-url")
 		verbose := viper.GetBool("frontend.gen-api.verbose")
 		skipValidation := viper.GetBool("frontend.gen-api.skip-validation")
 		errorsOnly := viper.GetBool("frontend.gen-api.validation-errors-only")
-		specPath := genAPISpec
+
+		opts := generator.APIOptions{
+			InputSpec:      genAPISpec,
+			OutputDir:      outputDir,
+			BaseURL:        baseURL,
+			Targets:        flagTargets(),
+			TemplatesDir:   genAPITemplates,
+			Validate:       genAPIRuntimeValidate,
+			Layout:         genAPILayout,
+			Verbose:        verbose,
+			SkipValidation: skipValidation,
+			ErrorsOnly:     errorsOnly,
+		}
 
 		// Check if a flag was explicitly provided
 		specProvided := cmd.Flags().Changed("spec")
+		if specProvided && genAPIContract != "" {
+			return fmt.Errorf("--contract selects a spec from radas.yml and cannot be combined with --spec")
+		}
 
 		// If spec was not explicitly provided, try to find radas.yml
 		if !specProvided {
@@ -69,31 +103,238 @@ var genAPICmd = &cobra.Command{
 				// Found radas.yml, try to parse it
 				cfg, err := ParseConfig(configPath)
 				if err == nil && len(cfg.Contract.API) > 0 {
-					// Use the first API spec from the configuration
-					baseDir := filepath.Dir(configPath)
-					specPath = ResolvePath(baseDir, cfg.Contract.API[0].Path)
-					
-					// Use the project name for output directory if it's not explicitly provided
-					if !cmd.Flags().Changed("output") {
-						outputDir = filepath.Join(baseDir, "__generated__/api")
+					contracts, err := cfg.APIContracts(filepath.Dir(configPath), genAPIContract)
+					if err != nil {
+						return err
 					}
-					
-					fmt.Printf("Using API spec from radas.yml: %s\n", specPath)
+					if cmd.Flags().Changed("output") && len(contracts) > 1 {
+						return fmt.Errorf("--output needs a single contract, select one with --contract")
+					}
+					manifest, err := generator.ReadManifest(manifestPath(configPath))
+					if err != nil {
+						return err
+					}
+					// gen-api always regenerates, the manifest only keeps
+					// unchanged files untouched and spots hand edits
+					return contractRun{
+						contracts: contracts,
+						options: func(contract APIContract) generator.APIOptions {
+							return contractOptions(cmd, contract, opts)
+						},
+						check:    genAPICheck,
+						offline:  genAPIOffline,
+						lockPath: filepath.Join(filepath.Dir(configPath), source.LockFileName),
+						manifest: manifest,
+						force:    true,
+					}.run(cmd)
 				}
 			}
 		}
+		if genAPIContract != "" {
+			return fmt.Errorf("--contract requires a radas.yml with API contracts")
+		}
 
 		// Verify the spec file exists
-		if _, err := os.Stat(specPath); os.IsNotExist(err) {
-			return fmt.Errorf("API spec file not found: %s", specPath)
+		if !source.IsRemote(opts.InputSpec) {
+			if _, err := os.Stat(opts.InputSpec); os.IsNotExist(err) {
+				return fmt.Errorf("API spec file not found: %s", opts.InputSpec)
+			}
+		}
+		resolved, err := source.Resolve(opts.InputSpec, source.Options{Offline: genAPIOffline})
+		if err != nil {
+			return err
 		}
+		opts.InputSpec = resolved.Path
 
 		if verbose {
-			fmt.Printf("Generating client code from: %s\n", specPath)
-			fmt.Printf("Output directory: %s\n", outputDir)
+			fmt.Printf("Generating client code from: %s\n", genAPISpec)
+			fmt.Printf("Output directory: %s\n", opts.OutputDir)
+		}
+
+		if genAPICheck {
+			stale, err := checkAPIDrift(opts)
+			if err != nil {
+				return err
+			}
+			if stale > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d generated file(s) in %s are out of date, run `radas fe gen-api` to update them", stale, opts.OutputDir)
+			}
+			fmt.Printf("✅ Generated API code in %s is up to date\n", opts.OutputDir)
+			return nil
 		}
 
 		// Call API generator with the new architecture
-		return generator.GenerateAPI(specPath, outputDir, baseURL, verbose, skipValidation, errorsOnly)
+		return generator.GenerateAPIContract(opts)
 	},
 }
+
+// flagTargets returns the targets selected with --target, --zodios, --hooks,
+// --stores and the deprecated --all, which stands for the default targets
+func flagTargets() []string {
+	targets := append([]string(nil), genAPITargets...)
+	for _, flag := range []struct {
+		target string
+		set    bool
+	}{{"zodios", genAPIZodios}, {"hooks", genAPIHooks}, {"stores", genAPIStores}} {
+		if (flag.set || genAPIAll) && !slices.Contains(targets, flag.target) {
+			targets = append(targets, flag.target)
+		}
+	}
+	return targets
+}
+
+// contractOptions merges a radas.yml contract with the command defaults;
+// flags given explicitly on the command line win over the contract
+func contractOptions(cmd *cobra.Command, contract APIContract, defaults generator.APIOptions) generator.APIOptions {
+	opts := defaults
+	opts.InputSpec = contract.Path
+	opts.Contract = contract.Name
+
+	if !cmd.Flags().Changed("output") {
+		opts.OutputDir = contract.Output
+	}
+	// radas.yml is the source of truth so gen and gen-api agree on output;
+	// without a base_url the flag, or its default, applies
+	if contract.BaseURL != "" && !cmd.Flags().Changed("base-url") {
+		opts.BaseURL = contract.BaseURL
+	}
+	if len(opts.Targets) == 0 {
+		opts.Targets = contract.Targets
+	}
+	if !cmd.Flags().Changed("templates") {
+		opts.TemplatesDir = contract.Templates
+	}
+	if contract.Layout != "" && !cmd.Flags().Changed("layout") {
+		opts.Layout = contract.Layout
+	}
+	if contract.Validation.Skip != nil && !cmd.Flags().Changed("skip-validation") {
+		opts.SkipValidation = *contract.Validation.Skip
+	}
+	if contract.Validation.ErrorsOnly != nil && !cmd.Flags().Changed("validation-errors-only") {
+		opts.ErrorsOnly = *contract.Validation.ErrorsOnly
+	}
+	if contract.Validation.Runtime != nil && !cmd.Flags().Changed("runtime-validation") {
+		opts.Validate = *contract.Validation.Runtime
+	}
+	opts.Pagination = parser.PaginationConventions{
+		CursorParams: contract.Pagination.CursorParams,
+		PageParams:   contract.Pagination.PageParams,
+		OffsetParams: contract.Pagination.OffsetParams,
+		SizeParams:   contract.Pagination.SizeParams,
+		NextFields:   contract.Pagination.NextFields,
+		ItemsFields:  contract.Pagination.ItemsFields,
+		TotalFields:  contract.Pagination.TotalFields,
+	}
+	return opts
+}
+
+// manifestPath returns where generation is recorded for a radas.yml
+func manifestPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "__generated__", generator.ManifestFileName)
+}
+
+// contractRun is one pass over the API contracts of a radas.yml
+type contractRun struct {
+	contracts []APIContract
+	options   func(APIContract) generator.APIOptions
+	check     bool                // verify generated files instead of writing them
+	offline   bool                // only use remote specs that are already cached
+	lockPath  string              // lock file recording the spec hashes used for generation
+	manifest  *generator.Manifest // skips unchanged contracts when set
+	force     bool                // regenerate contracts the manifest reports as unchanged
+}
+
+// run generates, or with check verifies, the client of every contract and
+// records the spec hashes in the lock file. Missing local specs are skipped
+// with a warning.
+func (r contractRun) run(cmd *cobra.Command) error {
+	lock, err := source.ReadLock(r.lockPath)
+	if err != nil {
+		return err
+	}
+
+	stale := 0
+	for _, contract := range r.contracts {
+		// Ensure the API spec exists
+		if !source.IsRemote(contract.Path) {
+			if _, err := os.Stat(contract.Path); os.IsNotExist(err) {
+				fmt.Printf("Warning: API spec %s does not exist, skipping\n", contract.Path)
+				continue
+			}
+		}
+
+		resolved, err := source.Resolve(contract.Path, source.Options{Offline: r.offline})
+		if err != nil {
+			return err
+		}
+		opts := r.options(contract)
+		opts.InputSpec = resolved.Path
+
+		label := contract.Path
+		if contract.Name != "" {
+			label = fmt.Sprintf("%s (%s)", contract.Name, contract.Path)
+		}
+
+		if r.check {
+			fmt.Printf("Checking API client for: %s\n", label)
+			n, err := checkAPIDrift(opts)
+			if err != nil {
+				return err
+			}
+			stale += n
+			continue
+		}
+
+		if r.manifest == nil {
+			fmt.Printf("Generating API client for: %s\n", label)
+			if err := generator.GenerateAPIContract(opts); err != nil {
+				return fmt.Errorf("failed to generate API client for %s: %w", label, err)
+			}
+		} else {
+			generated, err := r.manifest.GenerateAPI(path.Join("api", contract.Name), opts, r.force)
+			if err != nil {
+				return fmt.Errorf("failed to generate API client for %s: %w", label, err)
+			}
+			if generated {
+				fmt.Printf("Generated API client for: %s\n", label)
+			} else {
+				fmt.Printf("API client for %s is up to date, skipping\n", label)
+			}
+		}
+		lock.Record(contract.location, resolved)
+	}
+
+	if stale > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d generated file(s) are out of date, run `radas fe %s` to update them", stale, cmd.Name())
+	}
+	if r.check {
+		fmt.Println("✅ Generated API code is up to date")
+		return nil
+	}
+	if r.manifest != nil {
+		if err := r.manifest.Write(); err != nil {
+			return err
+		}
+	}
+	return lock.Write(r.lockPath)
+}
+
+// checkAPIDrift prints a unified diff for every generated file that no longer
+// matches the spec and returns how many files are stale
+func checkAPIDrift(opts generator.APIOptions) (int, error) {
+	drifts, err := generator.CheckAPI(opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check generated API code: %w", err)
+	}
+	for _, drift := range drifts {
+		if drift.Missing {
+			fmt.Printf("Missing generated file: %s\n", drift.Path)
+		} else {
+			fmt.Printf("Stale generated file: %s\n", drift.Path)
+		}
+		fmt.Print(drift.Diff)
+	}
+	return len(drifts), nil
+}
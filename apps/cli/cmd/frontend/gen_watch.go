@@ -0,0 +1,265 @@
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"radas/internal/frontend/generator"
+	"radas/internal/frontend/source"
+)
+
+// genWatchDebounce lets editors finish saving, and bursts of changes such as
+// a git checkout settle, before regenerating
+const genWatchDebounce = 250 * time.Millisecond
+
+// genWatchReady starts the line gen --watch prints once the first generation
+// is done, which fe dev --gen waits for before starting the dev server
+const genWatchReady = "👀 Watching"
+
+// watchGen regenerates whatever a change to radas.yml, a spec, custom
+// templates or design tokens affects until interrupted. Errors are reported
+// and generation resumes with the next change.
+func watchGen(cmd *cobra.Command, configPath, only string) error {
+	configPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watching: %w", err)
+	}
+	defer watcher.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	targets := watchTargets{configPath: configPath, only: only}
+	targets.project, err = loadGenProject(configPath, only)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+	watched := make(map[string]bool)
+	targets.sync(watcher, watched)
+	fmt.Printf("%s %s for changes, press Ctrl+C to stop\n", genWatchReady, configPath)
+
+	debounce := time.NewTimer(genWatchDebounce)
+	debounce.Stop()
+	changed := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nStopped watching")
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			changed[event.Name] = true
+			debounce.Reset(genWatchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Printf("⚠️ Watch error: %v\n", err)
+		case <-debounce.C:
+			targets = targets.regenerate(cmd, changed)
+			changed = make(map[string]bool)
+			targets.sync(watcher, watched)
+		}
+	}
+}
+
+// watchTargets maps changed files to the design tokens and contracts of
+// radas.yml they affect
+type watchTargets struct {
+	configPath string
+	only       string      // the --contract being watched, if any
+	project    *genProject // nil while radas.yml cannot be loaded
+}
+
+// regenerate regenerates what the changed files affect and returns the
+// targets to watch from now on. A changed radas.yml is reloaded and
+// regenerated as a whole; the manifest skips what did not change.
+func (w watchTargets) regenerate(cmd *cobra.Command, changed map[string]bool) watchTargets {
+	if changed[w.configPath] {
+		fmt.Printf("🔄 %s changed\n", filepath.Base(w.configPath))
+		project, err := loadGenProject(w.configPath, w.only)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			w.project = nil
+			return w
+		}
+		w.project = project
+		reportWatchRun(project.generate(cmd, project.designs, project.contracts))
+		return w
+	}
+	if w.project == nil {
+		return w
+	}
+
+	specFiles := make(map[string][]string, len(w.project.contracts))
+	for _, contract := range w.project.contracts {
+		specFiles[contract.Name] = contractSpecFiles(contract)
+	}
+
+	var designs []DesignContract
+	var contracts []APIContract
+	var files []string
+	for file := range changed {
+		affected := false
+		for _, design := range w.project.designs {
+			if within(ResolvePath(w.project.baseDir, design.Path), file) && !containsDesign(designs, design) {
+				designs = append(designs, design)
+				affected = true
+			}
+		}
+		for _, contract := range w.project.contracts {
+			if affectsContract(contract, specFiles[contract.Name], file) && !containsContract(contracts, contract) {
+				contracts = append(contracts, contract)
+				affected = true
+			}
+		}
+		if affected {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return w
+	}
+
+	sort.Strings(files)
+	for _, file := range files {
+		if rel, err := filepath.Rel(w.project.baseDir, file); err == nil {
+			file = rel
+		}
+		fmt.Printf("🔄 %s changed\n", file)
+	}
+	reportWatchRun(w.project.generate(cmd, designs, contracts))
+	return w
+}
+
+// sync watches the directories holding radas.yml, local specs and the files
+// they refer to, custom templates and design tokens, and stops watching those
+// no longer needed. Directories are watched without their subdirectories, so
+// token and template trees are added directory by directory.
+func (w watchTargets) sync(watcher *fsnotify.Watcher, watched map[string]bool) {
+	dirs := map[string]bool{filepath.Dir(w.configPath): true}
+	if w.project != nil {
+		for _, contract := range w.project.contracts {
+			for _, file := range contractSpecFiles(contract) {
+				dirs[filepath.Dir(file)] = true
+			}
+			if contract.Templates != "" {
+				addTree(dirs, contract.Templates)
+			}
+		}
+		for _, design := range w.project.designs {
+			addTree(dirs, ResolvePath(w.project.baseDir, design.Path))
+		}
+	}
+
+	for dir := range watched {
+		if !dirs[dir] {
+			watcher.Remove(dir)
+			delete(watched, dir)
+		}
+	}
+	for dir := range dirs {
+		if watched[dir] {
+			continue
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			fmt.Printf("⚠️ Cannot watch %s: %v\n", dir, err)
+			continue
+		}
+		watched[dir] = true
+	}
+}
+
+// affectsContract reports whether a changed file may change the client of a
+// contract: its spec, a file the spec refers to, listed in specFiles, or one
+// of its custom templates
+func affectsContract(contract APIContract, specFiles []string, file string) bool {
+	if contract.Templates != "" && within(contract.Templates, file) {
+		return true
+	}
+	for _, specFile := range specFiles {
+		if file == specFile {
+			return true
+		}
+	}
+	return false
+}
+
+// contractSpecFiles returns the local spec of a contract and the files it
+// refers to. A spec that cannot be read is still watched on its own, so
+// fixing it regenerates the contract.
+func contractSpecFiles(contract APIContract) []string {
+	if source.IsRemote(contract.Path) {
+		return nil
+	}
+	files, err := generator.SpecFiles(contract.Path)
+	if err != nil {
+		return []string{contract.Path}
+	}
+	return files
+}
+
+// addTree adds dir and every directory below it
+func addTree(dirs map[string]bool, dir string) {
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs[path] = true
+		}
+		return nil
+	})
+}
+
+// within reports whether file is dir or inside it
+func within(dir, file string) bool {
+	rel, err := filepath.Rel(dir, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func containsDesign(designs []DesignContract, design DesignContract) bool {
+	for _, d := range designs {
+		if d == design {
+			return true
+		}
+	}
+	return false
+}
+
+func containsContract(contracts []APIContract, contract APIContract) bool {
+	for _, c := range contracts {
+		if c.Name == contract.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// reportWatchRun prints the outcome of a regeneration while watching
+func reportWatchRun(err error) {
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	fmt.Println("✅ Regenerated, watching for changes")
+}
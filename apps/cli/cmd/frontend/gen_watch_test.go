@@ -0,0 +1,137 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// writeFiles writes files, keyed by slash separated path, below dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAffectsContract(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"specs/users.yaml":          "$ref: './schemas/user.yaml#/User'\n",
+		"specs/schemas/user.yaml":   "$ref: \"../../shared/common.yaml#/Id\"\n",
+		"shared/common.yaml":        "Id: {type: string}\n",
+		"specs/billing.yaml":        "openapi: 3.0.0\n",
+		"templates/hooks/x.ts.tmpl": "",
+	})
+	path := func(name string) string { return filepath.Join(dir, filepath.FromSlash(name)) }
+	contract := APIContract{Name: "users", Path: path("specs/users.yaml"), Templates: path("templates")}
+	specFiles := contractSpecFiles(contract)
+
+	cases := []struct {
+		file string
+		want bool
+	}{
+		{"specs/users.yaml", true},
+		{"specs/schemas/user.yaml", true},
+		{"shared/common.yaml", true}, // referenced through another file
+		{"templates/hooks/x.ts.tmpl", true},
+		{"specs/billing.yaml", false}, // next to the spec but not referenced
+		{"specs/radas.yml", false},
+		{"templates.yaml", false},
+	}
+	for _, tc := range cases {
+		if got := affectsContract(contract, specFiles, path(tc.file)); got != tc.want {
+			t.Errorf("affectsContract(%s) = %v, want %v", tc.file, got, tc.want)
+		}
+	}
+
+	remote := APIContract{Name: "remote", Path: "https://example.com/users.yaml"}
+	if files := contractSpecFiles(remote); len(files) != 0 {
+		t.Errorf("remote specs should not be watched, got %v", files)
+	}
+	missing := APIContract{Name: "missing", Path: path("specs/missing.yaml")}
+	if !affectsContract(missing, contractSpecFiles(missing), missing.Path) {
+		t.Errorf("a spec that cannot be read should still affect its contract")
+	}
+}
+
+func TestWithin(t *testing.T) {
+	dir := filepath.FromSlash("/a/b")
+	cases := []struct {
+		file string
+		want bool
+	}{
+		{"/a/b", true},
+		{"/a/b/c.tmpl", true},
+		{"/a/b/c/d.tmpl", true},
+		{"/a/bc", false},
+		{"/a/bc/d.tmpl", false},
+		{"/a", false},
+		{"/a/..b/c", false},
+	}
+	for _, tc := range cases {
+		if got := within(dir, filepath.FromSlash(tc.file)); got != tc.want {
+			t.Errorf("within(%s, %s) = %v, want %v", dir, tc.file, got, tc.want)
+		}
+	}
+}
+
+func TestWatchTargetsRegenerate(t *testing.T) {
+	t.Setenv("RADAS_PLAYGROUND", "")
+	spec, err := os.ReadFile(filepath.Join("..", "..", "internal", "frontend", "generator", "api", "testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	config := "name: shop\ncontract:\n  api:\n    - name: pets\n      path: specs/pets.yaml\n      templates: templates\n"
+	writeFiles(t, dir, map[string]string{
+		"radas.yml":        config,
+		"specs/pets.yaml":  string(spec),
+		"specs/notes.yaml": "notes: []\n",
+	})
+	configPath := filepath.Join(dir, "radas.yml")
+	project, err := loadGenProject(configPath, "")
+	if err != nil {
+		t.Fatalf("loadGenProject() error = %v", err)
+	}
+	w := watchTargets{configPath: configPath, project: project}
+	cmd := &cobra.Command{}
+	queries := filepath.Join(dir, "__generated__", "api", "pets", "queries.ts")
+
+	// A file the spec does not refer to leaves the contract alone
+	w = w.regenerate(cmd, map[string]bool{filepath.Join(dir, "specs", "notes.yaml"): true})
+	if _, err := os.Stat(queries); !os.IsNotExist(err) {
+		t.Fatalf("an unrelated file should not regenerate, stat error = %v", err)
+	}
+
+	// A custom template regenerates the contract using it
+	writeFiles(t, dir, map[string]string{"templates/hooks/queries.ts.tmpl": "// custom queries\n"})
+	w = w.regenerate(cmd, map[string]bool{filepath.Join(dir, "templates", "hooks", "queries.ts.tmpl"): true})
+	if got, err := os.ReadFile(queries); err != nil || string(got) != "// custom queries\n" {
+		t.Fatalf("the template change should regenerate queries.ts, got %q, error = %v", got, err)
+	}
+
+	// A changed radas.yml is reloaded and regenerated
+	writeFiles(t, dir, map[string]string{"radas.yml": config + "    - name: store\n      path: specs/pets.yaml\n"})
+	w = w.regenerate(cmd, map[string]bool{configPath: true})
+	if w.project == nil || len(w.project.contracts) != 2 {
+		t.Fatalf("radas.yml should be reloaded, project = %+v", w.project)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "__generated__", "api", "store", "client.ts")); err != nil {
+		t.Errorf("the added contract should be generated: %v", err)
+	}
+
+	// A broken radas.yml drops the project until it is fixed
+	writeFiles(t, dir, map[string]string{"radas.yml": "contract: ["})
+	if w = w.regenerate(cmd, map[string]bool{configPath: true}); w.project != nil {
+		t.Errorf("a broken radas.yml should drop the project")
+	}
+}
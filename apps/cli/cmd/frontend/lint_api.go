@@ -0,0 +1,138 @@
+package frontend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"radas/internal/frontend/lint"
+	"radas/internal/frontend/parser"
+	"radas/internal/frontend/source"
+)
+
+var (
+	lintAPISpec     string
+	lintAPIContract string
+	lintAPIFormat   string
+	lintAPIRules    map[string]string
+	lintAPIOffline  bool
+)
+
+func init() {
+	lintAPICmd.Flags().StringVarP(&lintAPISpec, "spec", "s", "", "OpenAPI specification file or http(s)/git+ssh URL (defaults to the contracts in radas.yml)")
+	lintAPICmd.Flags().StringVar(&lintAPIContract, "contract", "", "Lint only the named API contract from radas.yml")
+	lintAPICmd.Flags().StringVarP(&lintAPIFormat, "format", "f", "text", "Report format: text or json")
+	lintAPICmd.Flags().StringToStringVar(&lintAPIRules, "rule", nil, "Override a rule severity, e.g. --rule inline-schema=off")
+	lintAPICmd.Flags().BoolVar(&lintAPIOffline, "offline", false, "Use cached copies of remote specs without fetching")
+	lintAPICmd.Long += "\n\nRules:" + lintRuleList()
+}
+
+var lintAPICmd = &cobra.Command{
+	Use:   "lint-api",
+	Short: "Check OpenAPI specs for problems that degrade generated code",
+	Long: `Lint OpenAPI specs with a configurable rule set. Rules are enabled, disabled or
+given a severity (off, info, warning, error) per contract under lint.rules in
+radas.yml, or with --rule. Exits with status 1 when any error is reported.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if lintAPIFormat != "text" && lintAPIFormat != "json" {
+			return fmt.Errorf("unknown format %q (available: text, json)", lintAPIFormat)
+		}
+		if cmd.Flags().Changed("spec") && lintAPIContract != "" {
+			return fmt.Errorf("--contract selects a spec from radas.yml and cannot be combined with --spec")
+		}
+
+		var contracts []APIContract
+		if lintAPISpec != "" {
+			contracts = []APIContract{{Path: lintAPISpec}}
+		} else {
+			configPath, err := FindConfig()
+			if err != nil {
+				return fmt.Errorf("no --spec given and %w", err)
+			}
+			cfg, err := ParseConfig(configPath)
+			if err != nil {
+				return err
+			}
+			if contracts, err = cfg.APIContracts(filepath.Dir(configPath), lintAPIContract); err != nil {
+				return err
+			}
+			if len(contracts) == 0 {
+				return fmt.Errorf("no API contracts in %s, pass --spec", configPath)
+			}
+		}
+
+		type result struct {
+			Contract string       `json:"contract,omitempty"`
+			Spec     string       `json:"spec"`
+			Issues   []lint.Issue `json:"issues"`
+		}
+		var results []result
+		errors := 0
+		for _, contract := range contracts {
+			rules := make(map[string]string, len(contract.Lint.Rules)+len(lintAPIRules))
+			for id, severity := range contract.Lint.Rules {
+				rules[id] = severity
+			}
+			for id, severity := range lintAPIRules {
+				rules[id] = severity
+			}
+			ruleConfig, err := lint.ParseConfig(rules)
+			if err != nil {
+				return err
+			}
+
+			resolved, err := source.Resolve(contract.Path, source.Options{Offline: lintAPIOffline})
+			if err != nil {
+				return err
+			}
+			doc, err := parser.LoadOpenAPI(resolved.Path)
+			if err != nil {
+				return err
+			}
+			issues := lint.Lint(doc, ruleConfig)
+			if issues == nil {
+				issues = []lint.Issue{}
+			}
+			errors += lint.Count(issues, lint.Error)
+			results = append(results, result{Contract: contract.Name, Spec: contract.Path, Issues: issues})
+		}
+
+		if lintAPIFormat == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(results); err != nil {
+				return err
+			}
+		} else {
+			for _, r := range results {
+				name := r.Spec
+				if r.Contract != "" {
+					name = r.Contract + " (" + r.Spec + ")"
+				}
+				fmt.Printf("🔍 %s\n", name)
+				if err := lint.WriteText(os.Stdout, r.Issues); err != nil {
+					return err
+				}
+			}
+		}
+
+		if errors > 0 {
+			// The error goes to stderr, keeping the report on stdout clean
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d lint error(s)", errors)
+		}
+		return nil
+	},
+}
+
+// lintRuleList describes the built-in rules for the help text
+func lintRuleList() string {
+	var b strings.Builder
+	for _, rule := range lint.Rules() {
+		fmt.Fprintf(&b, "\n  %-22s %-8s %s", rule.ID, rule.Severity, rule.Description)
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"radas/internal/frontend/merge"
+	"radas/internal/frontend/source"
+)
+
+var (
+	mergeAPISources  []string
+	mergeAPIOutput   string
+	mergeAPIStrategy string
+	mergeAPITitle    string
+	mergeAPIContract string
+	mergeAPIOffline  bool
+)
+
+func init() {
+	mergeAPICmd.Flags().StringArrayVar(&mergeAPISources, "source", nil, "Spec to merge as namespace=path, repeat for each service (defaults to merge.sources in radas.yml)")
+	mergeAPICmd.Flags().StringVarP(&mergeAPIOutput, "output", "o", "./merged-api.json", "Merged spec to write, YAML when it ends in .yaml or .yml")
+	mergeAPICmd.Flags().StringVar(&mergeAPIStrategy, "strategy", "", "How to resolve operations and components declared by several specs: error, first or last")
+	mergeAPICmd.Flags().StringVar(&mergeAPITitle, "title", "", "Title of the merged spec")
+	mergeAPICmd.Flags().StringVar(&mergeAPIContract, "contract", "", "Merge only the named API contract from radas.yml")
+	mergeAPICmd.Flags().BoolVar(&mergeAPIOffline, "offline", false, "Use cached copies of remote specs without fetching")
+}
+
+var mergeAPICmd = &cobra.Command{
+	Use:   "merge-api",
+	Short: "Merge several OpenAPI specs into one contract",
+	Long: `Merge the OpenAPI specs of several services into the single contract gen-api
+consumes. Schemas, other components and operationIds are prefixed with the
+namespace of their service (users_User, users_getUser) so they are generated
+into that namespace. Operations or components declared by several specs fail
+the merge unless a strategy keeps the first or the last declaration.
+
+  radas fe merge-api --source users=users.yaml --source orders=orders.yaml
+  radas fe merge-api                 # contracts with merge.sources in radas.yml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(mergeAPISources) > 0 {
+			if mergeAPIContract != "" {
+				return fmt.Errorf("--contract selects sources from radas.yml and cannot be combined with --source")
+			}
+			sources := make([]MergeSource, 0, len(mergeAPISources))
+			for _, arg := range mergeAPISources {
+				namespace, path, ok := strings.Cut(arg, "=")
+				if !ok || namespace == "" || path == "" {
+					return fmt.Errorf("invalid --source %q, expected namespace=path", arg)
+				}
+				sources = append(sources, MergeSource{Namespace: namespace, Path: path})
+			}
+			return runMergeAPI(sources, mergeAPIStrategy, mergeAPITitle, mergeAPIOutput)
+		}
+
+		configPath, err := FindConfig()
+		if err != nil {
+			return fmt.Errorf("no --source given and %w", err)
+		}
+		cfg, err := ParseConfig(configPath)
+		if err != nil {
+			return err
+		}
+		contracts, err := cfg.APIContracts(filepath.Dir(configPath), mergeAPIContract)
+		if err != nil {
+			return err
+		}
+		merged := 0
+		for _, contract := range contracts {
+			if len(contract.Merge.Sources) == 0 {
+				continue
+			}
+			if source.IsRemote(contract.Path) {
+				return fmt.Errorf("contract %s merges into %s, which must be a local file", contract.Name, contract.Path)
+			}
+			strategy := contract.Merge.Strategy
+			if cmd.Flags().Changed("strategy") {
+				strategy = mergeAPIStrategy
+			}
+			title := contract.Merge.Title
+			if cmd.Flags().Changed("title") {
+				title = mergeAPITitle
+			}
+			if err := runMergeAPI(contract.Merge.Sources, strategy, title, contract.Path); err != nil {
+				return fmt.Errorf("contract %s: %w", contract.Name, err)
+			}
+			merged++
+		}
+		if merged == 0 {
+			return fmt.Errorf("no API contract in %s declares merge.sources, pass --source", configPath)
+		}
+		return nil
+	},
+}
+
+func runMergeAPI(sources []MergeSource, strategyName, title, output string) error {
+	strategy, err := merge.ParseStrategy(strategyName)
+	if err != nil {
+		return err
+	}
+	specs := make([]merge.Source, 0, len(sources))
+	for _, src := range sources {
+		resolved, err := source.Resolve(src.Path, source.Options{Offline: mergeAPIOffline})
+		if err != nil {
+			return err
+		}
+		specs = append(specs, merge.Source{Namespace: src.Namespace, Path: resolved.Path, PathPrefix: src.PathPrefix})
+	}
+
+	result, err := merge.Merge(specs, merge.Options{Strategy: strategy, Title: title})
+	if err != nil {
+		return err
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Printf("⚠️ %s, kept the %s declaration\n", conflict, strategy)
+	}
+	if err := result.Write(output); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Merged %d specs into %s\n", len(specs), output)
+	return nil
+}
@@ -0,0 +1,118 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"radas/internal/frontend/mock"
+	"radas/internal/frontend/parser"
+	"radas/internal/frontend/source"
+)
+
+var (
+	mockSpec     string
+	mockContract string
+	mockHost     string
+	mockPort     int
+	mockSeed     uint64
+	mockFixtures string
+	mockBasePath string
+	mockOffline  bool
+)
+
+func init() {
+	mockCmd.Flags().StringVarP(&mockSpec, "spec", "s", "", "OpenAPI specification file or http(s)/git+ssh URL (defaults to the contract in radas.yml)")
+	mockCmd.Flags().StringVar(&mockContract, "contract", "", "Serve the named API contract from radas.yml")
+	mockCmd.Flags().StringVar(&mockHost, "host", "127.0.0.1", "Address to listen on")
+	mockCmd.Flags().IntVarP(&mockPort, "port", "p", 4010, "Port to listen on")
+	mockCmd.Flags().Uint64Var(&mockSeed, "seed", 1, "Seed for generated data; the same seed always gives the same responses")
+	mockCmd.Flags().StringVar(&mockFixtures, "fixtures", "", "Directory of per-operation response overrides (<operationId>.json)")
+	mockCmd.Flags().StringVar(&mockBasePath, "base-path", "", "Path prefix the API is served under, e.g. /api")
+	mockCmd.Flags().BoolVar(&mockOffline, "offline", false, "Use cached copies of remote specs without fetching")
+}
+
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Serve a mock API from the OpenAPI contract",
+	Long: `Start a local HTTP server answering every operation of the OpenAPI contract.
+Requests are validated against the declared parameters and request body. Responses
+come from a fixture file named after the operationId, then from the examples in the
+spec, and otherwise from fake data generated from the response schema.
+Send "Prefer: code=404" or "Prefer: example=name" to pick another response.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("spec") && mockContract != "" {
+			return fmt.Errorf("--contract selects a spec from radas.yml and cannot be combined with --spec")
+		}
+
+		specPath := mockSpec
+		opts := mock.Options{
+			Seed:        mockSeed,
+			FixturesDir: mockFixtures,
+			BasePath:    mockBasePath,
+			Log:         os.Stdout,
+		}
+		port := mockPort
+		if specPath == "" {
+			contract, err := contractFromConfig(mockContract)
+			if err != nil {
+				return err
+			}
+			specPath = contract.Path
+			if !cmd.Flags().Changed("fixtures") {
+				opts.FixturesDir = contract.Mock.Fixtures
+			}
+			if !cmd.Flags().Changed("base-path") {
+				opts.BasePath = contract.Mock.BasePath
+			}
+			if !cmd.Flags().Changed("port") && contract.Mock.Port != 0 {
+				port = contract.Mock.Port
+			}
+		}
+
+		resolved, err := source.Resolve(specPath, source.Options{Offline: mockOffline})
+		if err != nil {
+			return err
+		}
+		spec, err := parser.ParseOpenAPI(resolved.Path, parser.OpenAPIOptions{ErrorsOnly: true})
+		if err != nil {
+			return fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+		}
+
+		addr := net.JoinHostPort(mockHost, strconv.Itoa(port))
+		server := &http.Server{Addr: addr, Handler: mock.New(spec, opts)}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- server.ListenAndServe()
+		}()
+
+		fmt.Printf("🚀 Mock API for %s listening on http://%s%s (%d operations)\n", specPath, addr, opts.BasePath, len(spec.Operations))
+		if opts.FixturesDir != "" {
+			fmt.Printf("Fixtures: %s\n", opts.FixturesDir)
+		}
+
+		select {
+		case err := <-errCh:
+			if !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("mock server failed: %w", err)
+			}
+			return nil
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			fmt.Println("\nStopping mock server...")
+			return server.Shutdown(shutdownCtx)
+		}
+	},
+}
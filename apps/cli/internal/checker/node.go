@@ -205,16 +205,30 @@ func InstallFrontendDependencies() {
 // StartApp starts an app using its package.json scripts
 // Preferred order: dev, start, serve
 func StartApp(appDir string) error {
+	cmd, err := AppCommand(appDir)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Run the command
+	return cmd.Run()
+}
+
+// AppCommand returns the command running the dev script of the app in appDir
+// with its package manager, without starting it
+func AppCommand(appDir string) (*exec.Cmd, error) {
 	// Check if package.json exists
 	packagePath := filepath.Join(appDir, "package.json")
 	if !utils.FileExists(packagePath) {
-		return fmt.Errorf("no package.json found in %s", appDir)
+		return nil, fmt.Errorf("no package.json found in %s", appDir)
 	}
 
 	// Read package.json
 	pkg, err := utils.ReadPackageJSON(packagePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Determine which script to run
@@ -227,7 +241,7 @@ func StartApp(appDir string) error {
 	}
 
 	if scriptName == "" {
-		return fmt.Errorf("no suitable start script found in package.json")
+		return nil, fmt.Errorf("no suitable start script found in package.json")
 	}
 
 	// Determine which package manager to use
@@ -244,13 +258,9 @@ func StartApp(appDir string) error {
 		cmd = exec.Command("npm", "run", scriptName)
 	}
 
-	// Set command directory and output
+	// Set command directory
 	cmd.Dir = appDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Run the command
-	return cmd.Run()
+	return cmd, nil
 }
 
 // FindAndListApps finds all available apps in the current directory or monorepo
@@ -0,0 +1,458 @@
+// Package apidiff compares two versions of an API contract and classifies
+// every change as breaking or non-breaking for the clients generated from it
+package apidiff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"radas/internal/frontend/parser"
+)
+
+// Change is a single difference between two versions of a contract
+type Change struct {
+	Breaking  bool   `json:"breaking"`
+	Rule      string `json:"rule"`               // stable identifier, e.g. operation-removed
+	Operation string `json:"operation"`          // METHOD /path
+	Location  string `json:"location,omitempty"` // parameter, body or response the change is in
+	Message   string `json:"message"`
+}
+
+// Report lists the changes between two contracts, breaking ones first
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// BreakingCount returns how many changes break existing clients
+func (r *Report) BreakingCount() int {
+	n := 0
+	for _, change := range r.Changes {
+		if change.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// direction tells whether a schema describes data sent or received by the
+// client; the same edit can be safe in one direction and breaking in the other
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+var pathParamPattern = regexp.MustCompile(`\{[^}]+\}`)
+
+// operationKey identifies an operation regardless of path parameter names
+func operationKey(op parser.Operation) string {
+	return strings.ToUpper(op.Method) + " " + pathParamPattern.ReplaceAllString(op.Path, "{}")
+}
+
+// Compare returns the changes from oldSpec to newSpec
+func Compare(oldSpec, newSpec *parser.ParsedSpec) *Report {
+	c := &comparer{
+		oldSchemas: schemaIndex(oldSpec),
+		newSchemas: schemaIndex(newSpec),
+		visiting:   make(map[[2]string]bool),
+	}
+
+	oldOps := operationIndex(oldSpec)
+	newOps := operationIndex(newSpec)
+	keys := make([]string, 0, len(oldOps)+len(newOps))
+	for key := range oldOps {
+		keys = append(keys, key)
+	}
+	for key := range newOps {
+		if _, ok := oldOps[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldOp, inOld := oldOps[key]
+		newOp, inNew := newOps[key]
+		switch {
+		case !inNew:
+			c.op = label(oldOp)
+			c.add(true, "operation-removed", "", "operation removed")
+		case !inOld:
+			c.op = label(newOp)
+			c.add(false, "operation-added", "", "operation added")
+		default:
+			c.op = label(newOp)
+			c.operation(oldOp, newOp)
+		}
+	}
+
+	// Breaking changes first, keeping the operation order within each group
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		return c.changes[i].Breaking && !c.changes[j].Breaking
+	})
+	return &Report{Changes: c.changes}
+}
+
+func label(op parser.Operation) string {
+	return strings.ToUpper(op.Method) + " " + op.Path
+}
+
+func schemaIndex(spec *parser.ParsedSpec) map[string]*parser.SchemaDef {
+	index := make(map[string]*parser.SchemaDef, len(spec.Schemas))
+	for _, schema := range spec.Schemas {
+		index[schema.Name] = schema.Def
+	}
+	return index
+}
+
+func operationIndex(spec *parser.ParsedSpec) map[string]parser.Operation {
+	index := make(map[string]parser.Operation, len(spec.Operations))
+	for _, op := range spec.Operations {
+		index[operationKey(op)] = op
+	}
+	return index
+}
+
+type comparer struct {
+	oldSchemas map[string]*parser.SchemaDef
+	newSchemas map[string]*parser.SchemaDef
+	visiting   map[[2]string]bool // schema pairs being compared, breaks reference cycles
+	op         string
+	changes    []Change
+}
+
+func (c *comparer) add(breaking bool, rule, location, format string, args ...interface{}) {
+	c.changes = append(c.changes, Change{
+		Breaking:  breaking,
+		Rule:      rule,
+		Operation: c.op,
+		Location:  location,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *comparer) operation(oldOp, newOp parser.Operation) {
+	if oldOp.ID != newOp.ID && oldOp.ID != "" && newOp.ID != "" {
+		c.add(true, "operation-id-changed", "", "operationId changed from %s to %s, renaming the generated client function", oldOp.ID, newOp.ID)
+	}
+	c.parameters(oldOp, newOp)
+	c.requestBody(oldOp.RequestBody, newOp.RequestBody)
+	c.responses(oldOp.Responses, newOp.Responses)
+}
+
+func (c *comparer) parameters(oldOp, newOp parser.Operation) {
+	oldParams := make(map[string]parser.Parameter)
+	for _, param := range oldOp.Parameters {
+		oldParams[param.In+" "+param.Name] = param
+	}
+	newParams := make(map[string]parser.Parameter)
+	for _, param := range newOp.Parameters {
+		newParams[param.In+" "+param.Name] = param
+	}
+
+	for _, key := range sortedKeys(oldParams) {
+		oldParam := oldParams[key]
+		where := oldParam.In + " parameter " + oldParam.Name
+		newParam, ok := newParams[key]
+		if !ok {
+			// Path parameters are matched by position through the operation key
+			if oldParam.In != "path" {
+				c.add(false, "parameter-removed", where, "parameter removed")
+			}
+			continue
+		}
+		if !oldParam.Required && newParam.Required {
+			c.add(true, "parameter-became-required", where, "parameter became required")
+		} else if oldParam.Required && !newParam.Required {
+			c.add(false, "parameter-became-optional", where, "parameter became optional")
+		}
+		if oldParam.Style != newParam.Style || oldParam.Explode != newParam.Explode {
+			c.add(true, "parameter-serialization-changed", where, "serialization changed from %s (explode=%v) to %s (explode=%v)",
+				oldParam.Style, oldParam.Explode, newParam.Style, newParam.Explode)
+		}
+		c.schema(where, oldParam.Def, newParam.Def, request)
+	}
+
+	for _, key := range sortedKeys(newParams) {
+		if _, ok := oldParams[key]; ok {
+			continue
+		}
+		param := newParams[key]
+		where := param.In + " parameter " + param.Name
+		if param.In == "path" {
+			continue
+		}
+		if param.Required {
+			c.add(true, "required-parameter-added", where, "new required parameter")
+		} else {
+			c.add(false, "optional-parameter-added", where, "new optional parameter")
+		}
+	}
+}
+
+func (c *comparer) requestBody(oldBody, newBody *parser.RequestBody) {
+	const where = "request body"
+	switch {
+	case oldBody == nil && newBody == nil:
+		return
+	case oldBody == nil:
+		if newBody.Required {
+			c.add(true, "required-request-body-added", where, "new required request body")
+		} else {
+			c.add(false, "request-body-added", where, "new optional request body")
+		}
+		return
+	case newBody == nil:
+		c.add(false, "request-body-removed", where, "request body removed")
+		return
+	}
+	if !oldBody.Required && newBody.Required {
+		c.add(true, "request-body-became-required", where, "request body became required")
+	}
+	c.schema(where, oldBody.Def, newBody.Def, request)
+}
+
+func (c *comparer) responses(oldResponses, newResponses map[string]parser.Response) {
+	for _, status := range sortedKeys(oldResponses) {
+		where := "response " + status
+		newResponse, ok := newResponses[status]
+		if !ok {
+			if isSuccess(status) {
+				c.add(true, "response-removed", where, "success response removed")
+			} else {
+				c.add(false, "response-removed", where, "response removed")
+			}
+			continue
+		}
+		oldDef := oldResponses[status].Def
+		if oldDef != nil && newResponse.Def == nil {
+			c.add(isSuccess(status), "response-body-removed", where, "response body removed")
+			continue
+		}
+		c.schema(where, oldDef, newResponse.Def, response)
+	}
+	for _, status := range sortedKeys(newResponses) {
+		if _, ok := oldResponses[status]; !ok {
+			c.add(false, "response-added", "response "+status, "new response")
+		}
+	}
+}
+
+func isSuccess(status string) bool {
+	return strings.HasPrefix(status, "2")
+}
+
+// flatSchema is a schema with references resolved and allOf members merged
+type flatSchema struct {
+	Type       string
+	Nullable   bool
+	Enum       []interface{}
+	Properties map[string]parser.Property
+	Items      *parser.SchemaDef
+	Variants   []string // names of the oneOf/anyOf variants
+}
+
+func flatten(def *parser.SchemaDef, schemas map[string]*parser.SchemaDef) flatSchema {
+	flat := flatSchema{Properties: make(map[string]parser.Property)}
+	flattenInto(&flat, def, schemas, 0)
+	return flat
+}
+
+func flattenInto(flat *flatSchema, def *parser.SchemaDef, schemas map[string]*parser.SchemaDef, depth int) {
+	def = resolve(def, schemas)
+	if def == nil || depth > 16 {
+		return
+	}
+	if def.Type != "" {
+		flat.Type = def.Type
+	}
+	flat.Nullable = flat.Nullable || def.Nullable
+	if len(def.Enum) > 0 {
+		flat.Enum = def.Enum
+	}
+	if def.Items != nil {
+		flat.Items = def.Items
+	}
+	for _, prop := range def.Properties {
+		flat.Properties[prop.Name] = prop
+	}
+	for _, variant := range append(append([]*parser.SchemaDef(nil), def.OneOf...), def.AnyOf...) {
+		name := variant.Ref
+		if name == "" {
+			name = variant.Type
+		}
+		flat.Variants = append(flat.Variants, name)
+	}
+	for _, member := range def.AllOf {
+		flattenInto(flat, member, schemas, depth+1)
+	}
+	if flat.Type == "" && len(flat.Properties) > 0 {
+		flat.Type = "object"
+	}
+}
+
+func resolve(def *parser.SchemaDef, schemas map[string]*parser.SchemaDef) *parser.SchemaDef {
+	for i := 0; def != nil && def.Ref != "" && i < 32; i++ {
+		def = schemas[def.Ref]
+	}
+	return def
+}
+
+// schema reports the differences between two schemas at where
+func (c *comparer) schema(where string, oldDef, newDef *parser.SchemaDef, dir direction) {
+	if oldDef == nil || newDef == nil {
+		return
+	}
+	if oldDef.Ref != "" || newDef.Ref != "" {
+		pair := [2]string{oldDef.Ref, newDef.Ref}
+		if c.visiting[pair] {
+			return
+		}
+		c.visiting[pair] = true
+		defer delete(c.visiting, pair)
+	}
+
+	oldFlat := flatten(oldDef, c.oldSchemas)
+	newFlat := flatten(newDef, c.newSchemas)
+
+	if oldFlat.Type != newFlat.Type && oldFlat.Type != "" && newFlat.Type != "" {
+		// Requests may widen integers to numbers, clients never send fractions
+		widened := dir == request && oldFlat.Type == "integer" && newFlat.Type == "number"
+		c.add(!widened, "type-changed", where, "type changed from %s to %s", oldFlat.Type, newFlat.Type)
+		return
+	}
+
+	if oldFlat.Nullable != newFlat.Nullable {
+		if newFlat.Nullable {
+			c.add(dir == response, "became-nullable", where, "value became nullable")
+		} else {
+			c.add(dir == request, "became-non-nullable", where, "value is no longer nullable")
+		}
+	}
+
+	c.enum(where, oldFlat.Enum, newFlat.Enum, dir)
+	c.variants(where, oldFlat.Variants, newFlat.Variants, dir)
+	c.properties(where, oldFlat.Properties, newFlat.Properties, dir)
+
+	if oldFlat.Items != nil && newFlat.Items != nil {
+		c.schema(where+"[]", oldFlat.Items, newFlat.Items, dir)
+	}
+}
+
+// enum classifies enum changes: clients can no longer send removed values,
+// and may not handle values they receive for the first time
+func (c *comparer) enum(where string, oldEnum, newEnum []interface{}, dir direction) {
+	switch {
+	case len(oldEnum) == 0 && len(newEnum) == 0:
+		return
+	case len(oldEnum) == 0:
+		c.add(dir == request, "enum-added", where, "values restricted to %s", formatValues(newEnum))
+		return
+	case len(newEnum) == 0:
+		c.add(dir == response, "enum-removed", where, "values are no longer restricted to an enum")
+		return
+	}
+
+	removed := missingValues(oldEnum, newEnum)
+	added := missingValues(newEnum, oldEnum)
+	if len(removed) > 0 {
+		c.add(dir == request, "enum-narrowed", where, "enum values removed: %s", formatValues(removed))
+	}
+	if len(added) > 0 {
+		c.add(dir == response, "enum-widened", where, "enum values added: %s", formatValues(added))
+	}
+}
+
+func (c *comparer) variants(where string, oldVariants, newVariants []string, dir direction) {
+	removed := missingStrings(oldVariants, newVariants)
+	added := missingStrings(newVariants, oldVariants)
+	if len(removed) > 0 {
+		c.add(dir == request, "variant-removed", where, "variants removed: %s", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		c.add(dir == response, "variant-added", where, "variants added: %s", strings.Join(added, ", "))
+	}
+}
+
+func (c *comparer) properties(where string, oldProps, newProps map[string]parser.Property, dir direction) {
+	for _, name := range sortedKeys(oldProps) {
+		oldProp := oldProps[name]
+		propWhere := where + "." + name
+		newProp, ok := newProps[name]
+		if !ok {
+			if dir == response {
+				c.add(true, "response-property-removed", propWhere, "property removed")
+			} else {
+				c.add(false, "request-property-removed", propWhere, "property removed")
+			}
+			continue
+		}
+		switch {
+		case !oldProp.Required && newProp.Required:
+			c.add(dir == request, "property-became-required", propWhere, "property became required")
+		case oldProp.Required && !newProp.Required:
+			c.add(dir == response, "property-became-optional", propWhere, "property became optional")
+		}
+		c.schema(propWhere, oldProp.Def, newProp.Def, dir)
+	}
+
+	for _, name := range sortedKeys(newProps) {
+		if _, ok := oldProps[name]; ok {
+			continue
+		}
+		prop := newProps[name]
+		if dir == request && prop.Required {
+			c.add(true, "required-property-added", where+"."+name, "new required property")
+		} else {
+			c.add(false, "property-added", where+"."+name, "new property")
+		}
+	}
+}
+
+func missingValues(values, others []interface{}) []interface{} {
+	present := make(map[string]bool, len(others))
+	for _, value := range others {
+		present[fmt.Sprint(value)] = true
+	}
+	var missing []interface{}
+	for _, value := range values {
+		if !present[fmt.Sprint(value)] {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+func missingStrings(values, others []string) []string {
+	present := make(map[string]bool, len(others))
+	for _, value := range others {
+		present[value] = true
+	}
+	var missing []string
+	for _, value := range values {
+		if !present[value] {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+func formatValues(values []interface{}) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, fmt.Sprint(value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+package apidiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"radas/internal/frontend/parser"
+)
+
+const oldSpec = `openapi: 3.0.3
+info: {title: pets, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: status, in: query, schema: {type: string, enum: [available, adopted, lost]}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+    delete:
+      operationId: deletePet
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}}
+      responses:
+        "204": {description: deleted}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        nickname: {type: string}
+        kind: {type: string, enum: [cat, dog]}
+`
+
+const newSpec = `openapi: 3.0.3
+info: {title: pets, version: "2"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: integer}}
+        - {name: status, in: query, schema: {type: string, enum: [available, adopted]}}
+        - {name: sort, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog, bird]}
+        age: {type: integer}
+`
+
+func parseSpec(t *testing.T, content string) *parser.ParsedSpec {
+	t.Helper()
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := parser.ParseOpenAPI(specPath)
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+	return spec
+}
+
+func TestCompare(t *testing.T) {
+	report := Compare(parseSpec(t, oldSpec), parseSpec(t, newSpec))
+
+	want := map[string]bool{
+		"DELETE /pets/{petId} operation-removed":                         true,
+		"GET /pets query parameter limit parameter-became-required":      true,
+		"GET /pets query parameter status enum-narrowed":                 true,
+		"GET /pets query parameter sort optional-parameter-added":        false,
+		"GET /pets/{id} response 200.nickname response-property-removed": true,
+		"GET /pets/{id} response 200.kind enum-widened":                  true,
+		"GET /pets/{id} response 200.age property-added":                 false,
+	}
+	got := make(map[string]bool)
+	for _, change := range report.Changes {
+		got[strings.TrimSpace(change.Operation+" "+change.Location)+" "+change.Rule] = change.Breaking
+	}
+	for key, breaking := range want {
+		gotBreaking, ok := got[key]
+		if !ok {
+			t.Errorf("missing change %q", key)
+		} else if gotBreaking != breaking {
+			t.Errorf("%q breaking = %v, want %v", key, gotBreaking, breaking)
+		}
+	}
+	// The renamed path parameter is the same operation
+	for key := range got {
+		if strings.Contains(key, "operation-added") {
+			t.Errorf("unexpected change %q", key)
+		}
+	}
+	if !report.Changes[0].Breaking {
+		t.Errorf("breaking changes should be listed first")
+	}
+
+	var out bytes.Buffer
+	if err := report.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct{ Breaking int }
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded.Breaking != report.BreakingCount() {
+		t.Errorf("JSON report = %s", out.String())
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	spec := parseSpec(t, oldSpec)
+	report := Compare(spec, spec)
+	if len(report.Changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", report.Changes)
+	}
+	var out bytes.Buffer
+	if err := report.Write(&out, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "No API changes") {
+		t.Errorf("markdown report = %s", out.String())
+	}
+}
@@ -0,0 +1,121 @@
+package apidiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats lists the report formats accepted by Write
+var Formats = []string{"text", "markdown", "json"}
+
+// Write renders the report in the given format
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case "text", "":
+		return r.WriteText(w)
+	case "markdown", "md":
+		return r.WriteMarkdown(w)
+	case "json":
+		return r.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteText renders the report for a terminal
+func (r *Report) WriteText(w io.Writer) error {
+	if len(r.Changes) == 0 {
+		_, err := fmt.Fprintln(w, "No API changes")
+		return err
+	}
+	var b strings.Builder
+	breaking := r.BreakingCount()
+	for _, group := range r.groups() {
+		if len(group.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s (%d):\n", group.title, len(group.changes))
+		for _, change := range group.changes {
+			fmt.Fprintf(&b, "  %s %s", group.marker, change.Operation)
+			if change.Location != "" {
+				fmt.Fprintf(&b, " %s", change.Location)
+			}
+			fmt.Fprintf(&b, ": %s [%s]\n", change.Message, change.Rule)
+		}
+	}
+	fmt.Fprintf(&b, "\n%d breaking, %d non-breaking\n", breaking, len(r.Changes)-breaking)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown renders the report as a pull request comment
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("## API changes\n\n")
+	if len(r.Changes) == 0 {
+		b.WriteString("No API changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	breaking := r.BreakingCount()
+	fmt.Fprintf(&b, "**%d breaking**, %d non-breaking\n", breaking, len(r.Changes)-breaking)
+	for _, group := range r.groups() {
+		if len(group.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", group.title)
+		b.WriteString("| Operation | Location | Change | Rule |\n|---|---|---|---|\n")
+		for _, change := range group.changes {
+			location := ""
+			if change.Location != "" {
+				location = "`" + escapeCell(change.Location) + "`"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n",
+				escapeCell(change.Operation), location, escapeCell(change.Message), change.Rule)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON renders the report for other tools
+func (r *Report) WriteJSON(w io.Writer) error {
+	breaking := r.BreakingCount()
+	changes := r.Changes
+	if changes == nil {
+		changes = []Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Breaking    int      `json:"breaking"`
+		NonBreaking int      `json:"non_breaking"`
+		Changes     []Change `json:"changes"`
+	}{breaking, len(changes) - breaking, changes})
+}
+
+type changeGroup struct {
+	title   string
+	marker  string
+	changes []Change
+}
+
+func (r *Report) groups() []changeGroup {
+	groups := []changeGroup{
+		{title: "Breaking changes", marker: "✗"},
+		{title: "Non-breaking changes", marker: "•"},
+	}
+	for _, change := range r.Changes {
+		if change.Breaking {
+			groups[0].changes = append(groups[0].changes, change)
+		} else {
+			groups[1].changes = append(groups[1].changes, change)
+		}
+	}
+	return groups
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
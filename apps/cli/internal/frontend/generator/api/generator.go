@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"radas/internal/frontend/parser"
+	"radas/internal/utils"
 )
 
 // templateFuncs contains helper functions for templates
@@ -24,8 +28,9 @@ var templateFuncs = template.FuncMap{
 	"camelCase":              camelCase,
 	"contains":               contains,
 	"last":                  last,
-	"schemaToZod":            schemaToZodTemplate,
-	"tsType":                 goTypeToTSType,
+	"tsType":                 tsType,
+	"dtoType":                dtoType,
+	"tsDeclaration":          tsDeclaration,
 	"isRequired":             isRequired,
 	"hasErrorResponses":      hasErrorResponses,
 	"isErrorStatus":          isErrorStatus,
@@ -38,263 +43,311 @@ var templateFuncs = template.FuncMap{
 	"hasPathParams":          hasPathParams,
 	"hasQueryParams":         hasQueryParams,
 	"hasHeaderParams":        hasHeaderParams,
-	"returnTypePromise":      returnTypePromise,
-	"shouldInvalidateQueries": shouldInvalidateQueries,
-	"hasRelatedGetOperation":  hasRelatedGetOperation,
-	"getRelatedListOperation": getRelatedListOperation,
-	"getRelatedGetOperation":  getRelatedGetOperation,
+	"returnTypePromise":      returnTypeTemplate,
+	"cacheUpdates":            cacheUpdates,
+	"keyNamespace":            keyNamespace,
+	"queryKey":                queryKey,
 	"pathWithParams":         pathWithParams,
 	"pathToTemplate":         pathToTemplate,
 	"zodType":                zodType,
 	"getSuccessResponseSchema": getSuccessResponseSchema,
+	"errorType":              errorType,
+	"errorTypeName":          errorTypeName,
+	"bodyKind":               bodyKind,
+	"requestBodyType":        requestBodyType,
+	"responseKind":           responseKind,
+	"successContentType":     successContentType,
+	"isJSONOperation":        isJSONOperation,
+	"hasEncodedBodies":       hasEncodedBodies,
+	"securityLiteral":        securityLiteral,
+	"credentialType":         credentialType,
+	"propertyKey":            propertyKey,
+	"jsLiteral":              jsLiteral,
+	"hasPagination":          hasPagination,
+	"initialPageParam":       initialPageParam,
+	"nextPageParam":          nextPageParam,
 }
 
 type Config struct {
 	InputSpec      string
 	OutputDir      string
 	BaseURL        string
-	GenerateAll    bool
-	ZodiosOnly     bool
-	HooksOnly      bool
-	StoresOnly     bool
+	Contract       string   // scopes query keys when several contracts share a QueryClient
+	Targets        []string // registry targets to render, DefaultTargets when empty
+	TemplatesDir   string   // user targets layered over the built-in ones
+	Validate       bool     // fetch and axios clients check bodies against Zod schemas at runtime
 	Verbose        bool
 	SkipValidation bool
 	ErrorsOnly     bool
+	Pagination     parser.PaginationConventions // names that mark list operations as paginated
+	Layout         string                       // LayoutSingle (default) or LayoutNamespaces
 }
 
 type Generator struct {
 	config *Config
 }
 
-func New(config *Config) *Generator {
-	return &Generator{config: config}
+// Timing records how long a generation stage took, reported with Verbose
+type Timing struct {
+	Stage    string
+	Duration time.Duration
 }
 
-func (g *Generator) Generate() error {
-	if g.config.Verbose {
-		fmt.Printf("[GEN] Parsing OpenAPI spec: %s\n", g.config.InputSpec)
-	}
+// Timings lists the stages of one generation in the order they ran
+type Timings []Timing
 
-	// Pass validation flags to the parser
-	parserOptions := parser.OpenAPIOptions{
-		SkipValidation: g.config.SkipValidation,
-		ErrorsOnly:     g.config.ErrorsOnly,
+// Print prints the stages followed by the total. Concurrent renders overlap,
+// so their durations add up to more than the wall time.
+func (t Timings) Print(total time.Duration) {
+	width := len("total")
+	for _, timing := range t {
+		width = max(width, len(timing.Stage))
+	}
+	fmt.Println("[GEN] Timing breakdown:")
+	for _, timing := range t {
+		fmt.Printf("  %-*s %s\n", width, timing.Stage, timing.Duration.Round(time.Microsecond))
 	}
+	fmt.Printf("  %-*s %s\n", width, "total", total.Round(time.Microsecond))
+}
 
-	spec, err := parser.ParseOpenAPI(g.config.InputSpec, parserOptions)
+func New(config *Config) *Generator {
+	return &Generator{config: config}
+}
+
+// FileDrift describes a generated file whose content on disk no longer
+// matches a fresh render of the spec
+type FileDrift struct {
+	Path    string
+	Missing bool
+	Diff    string
+}
+
+func (g *Generator) Generate() error {
+	start := time.Now()
+	files, timings, err := g.Render()
 	if err != nil {
-		return fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+		return err
 	}
 
+	writeStart := time.Now()
+
 	// Create output directory
 	if err := os.MkdirAll(g.config.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	if g.config.GenerateAll || g.config.ZodiosOnly {
-		if err := g.generateZodios(spec); err != nil {
-			return fmt.Errorf("failed to generate Zodios client: %w", err)
+	for _, name := range sortedFileNames(files) {
+		filePath := filepath.Join(g.config.OutputDir, name)
+		// Unchanged files keep their mtime so dev servers do not reload
+		if current, err := os.ReadFile(filePath); err == nil && string(current) == files[name] {
+			continue
 		}
-	}
-	if g.config.GenerateAll || g.config.HooksOnly {
-		if err := g.generateQueries(spec); err != nil {
-			return fmt.Errorf("failed to generate React Query hooks: %w", err)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(filePath), err)
 		}
-	}
-	if g.config.GenerateAll || g.config.StoresOnly {
-		if err := g.generateZustand(); err != nil {
-			return fmt.Errorf("failed to generate Zustand stores: %w", err)
+		if err := os.WriteFile(filePath, []byte(files[name]), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filePath, err)
 		}
 	}
-	// Always generate DTOs
-	if err := g.generateDTO(); err != nil {
-		return fmt.Errorf("failed to generate DTOs: %w", err)
-	}
+
+	timings = append(timings, Timing{"write", time.Since(writeStart)})
 
 	if g.config.Verbose {
+		timings.Print(time.Since(start))
 		fmt.Printf("✅ Code generation completed in: %s\n", g.config.OutputDir)
 	}
 	return nil
 }
 
-// execTemplate executes a template with the given data and returns the result as a string
-func (g *Generator) execTemplate(templateName string, data interface{}) (string, error) {
-	// Get template content from embedded templates
-	templateContent, exists := templates[templateName]
-	if !exists {
-		return "", fmt.Errorf("template %s not found in embedded templates", templateName)
-	}
-	
-	// Parse the template
-	tmpl, err := template.New(templateName).Funcs(templateFuncs).Parse(templateContent)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template %s: %w", templateName, err)
-	}
-	
-	buf := new(bytes.Buffer)
-	err = tmpl.Execute(buf, data)
+// Render generates every output file in memory without touching the output
+// directory, along with how long each stage took
+func (g *Generator) Render() (map[string]string, Timings, error) {
+	start := time.Now()
+	spec, err := g.Parse()
 	if err != nil {
-		return "", fmt.Errorf("failed to execute template %s: %w", templateName, err)
+		return nil, nil, err
 	}
-	
-	return buf.String(), nil
+	parsed := Timing{"parse", time.Since(start)}
+	files, timings, err := g.RenderSpec(spec)
+	return files, append(Timings{parsed}, timings...), err
 }
 
-func (g *Generator) generateZodios(spec *parser.ParsedSpec) error {
+// Parse loads, validates and parses the spec once for all targets
+func (g *Generator) Parse() (*parser.ParsedSpec, error) {
 	if g.config.Verbose {
-		fmt.Println("[GEN] Generating Zodios client...")
+		fmt.Printf("[GEN] Parsing OpenAPI spec: %s\n", g.config.InputSpec)
 	}
 
-	// Create template data with BaseURL
-	templateData := struct {
-		*parser.ParsedSpec
-		BaseURL string
-	}{
-		ParsedSpec: spec,
-		BaseURL:    g.config.BaseURL,
+	// Pass validation flags to the parser
+	parserOptions := parser.OpenAPIOptions{
+		SkipValidation: g.config.SkipValidation,
+		ErrorsOnly:     g.config.ErrorsOnly,
+		Pagination:     g.config.Pagination,
 	}
 
-	content, err := g.execTemplate("client.tmpl", templateData)
+	spec, err := parser.ParseOpenAPI(g.config.InputSpec, parserOptions)
 	if err != nil {
-		return fmt.Errorf("failed to generate client: %w", err)
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
 	}
-
-	return g.writeFile("client.ts", content)
+	return spec, nil
 }
 
-// generateSchemasTS menghasilkan kode TypeScript untuk semua schemas dan mengembalikan urutan nama schema
-func generateSchemasTS(spec *parser.ParsedSpec) (string, []string) {
-	var out strings.Builder
-	var names []string
-	for _, s := range spec.Schemas {
-		ts := schemaToZod(s)
-		out.WriteString(fmt.Sprintf("const %s = %s\n", s.Name, ts))
-		names = append(names, s.Name)
+// RenderSpec renders every output file from an already parsed spec. The spec
+// is only read, so one parse can back several renders.
+func (g *Generator) RenderSpec(spec *parser.ParsedSpec) (map[string]string, Timings, error) {
+	start := time.Now()
+	registry, err := NewRegistry(g.config.TemplatesDir)
+	if err != nil {
+		return nil, nil, err
 	}
-	return out.String(), names
-}
-
-// schemaToZod mengubah schema Go ke kode zod object
-func schemaToZod(s parser.Schema) string {
-	var props []string
-	for k, t := range s.Properties {
-		props = append(props, fmt.Sprintf("%s: %s", k, goTypeToZod(fmt.Sprintf("%v", t))))
+	templates, err := registry.Templates(g.config.Targets)
+	if err != nil {
+		return nil, nil, err
 	}
-	return fmt.Sprintf("z.object({ %s }).partial().passthrough()", strings.Join(props, ", "))
-}
 
-// goTypeToZod mengubah tipe Go/schema ke string zod
-func goTypeToZod(t string) string {
-	// Check if the type includes nullable information
-	isNullable := false
-	if strings.HasSuffix(t, " | null") {
-		isNullable = true
-		t = strings.TrimSuffix(t, " | null")
+	partials := make(map[string]string)
+	for name, content := range templates {
+		if isPartial(name) {
+			partials[name] = content
+		}
 	}
-	
-	var zodType string
-	switch t {
-	case "string":
-		zodType = "z.string()"
-	case "number":
-		zodType = "z.number()"
-	case "boolean":
-		zodType = "z.boolean()"
-	case "array":
-		zodType = "z.array(z.any())"
-	case "object":
-		zodType = "z.object({}).passthrough()"
-	case "null":
-		return "z.null()" // Direct null type from OpenAPI 3.1.0
+	timings := Timings{{"templates", time.Since(start)}}
+
+	switch g.config.Layout {
+	case "", LayoutSingle:
+	case LayoutNamespaces:
+		files, err := g.renderNamespaces(templates, partials, spec, &timings)
+		return files, timings, err
 	default:
-		if strings.HasSuffix(t, "Schema") || strings.HasPrefix(t, "z.") {
-			return t // Already a Zod schema reference
-		}
-		zodType = "z.any()"
+		return nil, nil, fmt.Errorf("unknown layout %q (available: %s, %s)", g.config.Layout, LayoutSingle, LayoutNamespaces)
 	}
-	
-	// Add nullable() if needed
-	if isNullable {
-		return zodType + ".nullable()"
+
+	files := make(map[string]string, len(templates))
+	if err := g.renderFiles(files, templates, partials, g.templateData(spec), "", &timings, func(string) bool { return true }); err != nil {
+		return nil, nil, err
 	}
-	
-	return zodType
+	return files, timings, nil
 }
 
-
-func (g *Generator) generateQueries(spec *parser.ParsedSpec) error {
-	if g.config.Verbose {
-		fmt.Println("[GEN] Generating React Query hooks...")
+// renderFiles renders the templates accepted by include into files, naming
+// the output after the template inside dir. Templates only read data, so the
+// files are rendered concurrently. The time each one took is added to timings.
+func (g *Generator) renderFiles(files, templates, partials map[string]string, data templateData, dir string, timings *Timings, include func(name string) bool) error {
+	var names []string
+	for _, name := range sortedFileNames(templates) {
+		if !isPartial(name) && include(name) {
+			names = append(names, name)
+		}
 	}
 
-	// First generate the queryClient file
-	queryClientData := struct {
-		BaseURL string
-	}{
-		BaseURL: g.config.BaseURL,
+	contents := make([]string, len(names))
+	errs := make([]error, len(names))
+	durations := make([]time.Duration, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			contents[i], errs[i] = renderTemplate(name, templates[name], partials, data)
+			durations[i] = time.Since(start)
+		}()
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		*timings = append(*timings, Timing{"render " + dir + name, durations[i]})
+		// A template that renders nothing drops the file, which lets
+		// overrides remove files they would otherwise inherit
+		if strings.TrimSpace(contents[i]) == "" {
+			continue
+		}
+		files[dir+name] = contents[i]
 	}
-	queryClientContent, err := g.execTemplate("queryClient.tmpl", queryClientData)
+	return nil
+}
+
+// Check renders the output in memory and compares it with the files in the
+// output directory. Files that only exist on disk are ignored.
+func (g *Generator) Check() ([]FileDrift, error) {
+	start := time.Now()
+	files, timings, err := g.Render()
 	if err != nil {
-		return fmt.Errorf("failed to generate query client: %w", err)
+		return nil, err
 	}
-
-	if err := g.writeFile("queryClient.ts", queryClientContent); err != nil {
-		return err
+	if g.config.Verbose {
+		defer func() { timings.Print(time.Since(start)) }()
 	}
 
-	// Group operations by namespace/entity for better organization
-	groupedOps := make(map[string][]parser.Operation)
-	for _, op := range spec.Operations {
-		namespace := op.Namespace
-		if namespace == "" {
-			namespace = "api"
+	var drifts []FileDrift
+	for _, name := range sortedFileNames(files) {
+		filePath := filepath.Join(g.config.OutputDir, name)
+		current, err := os.ReadFile(filePath)
+		missing := os.IsNotExist(err)
+		if err != nil && !missing {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		if !missing && string(current) == files[name] {
+			continue
 		}
-		groupedOps[namespace] = append(groupedOps[namespace], op)
-	}
 
-	// Create data for the template
-	templateData := struct {
-		GroupedOps map[string][]parser.Operation
-		Spec       *parser.ParsedSpec
-		BaseURL    string
-	}{
-		GroupedOps: groupedOps,
-		Spec:       spec,
-		BaseURL:    g.config.BaseURL,
+		fromName, toName := diffLabels(filePath)
+		if missing {
+			fromName = "/dev/null"
+		}
+		drifts = append(drifts, FileDrift{
+			Path:    filePath,
+			Missing: missing,
+			Diff:    utils.UnifiedDiff(fromName, toName, string(current), files[name]),
+		})
 	}
+	return drifts, nil
+}
 
-	// Generate queries content using template
-	content, err := g.execTemplate("queries.tmpl", templateData)
-	if err != nil {
-		return fmt.Errorf("failed to generate queries: %w", err)
+// diffLabels returns git-style a/ and b/ labels relative to the working
+// directory, falling back to the plain path outside of it
+func diffLabels(filePath string) (string, string) {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+			rel = filepath.ToSlash(rel)
+			return "a/" + rel, "b/" + rel
+		}
 	}
-
-	return g.writeFile("queries.ts", content)
+	return filePath, filePath
 }
 
-func capitalize(s string) string {
-	if len(s) == 0 {
-		return s
+func sortedFileNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
 	}
-	return strings.ToUpper(s[:1]) + s[1:]
+	sort.Strings(names)
+	return names
 }
 
-func (g *Generator) generateZustand() error {
-	if g.config.Verbose {
-		fmt.Println("[GEN] Generating Zustand stores...")
-	}
-	
-	// First we need to get the parsed spec to group operations by namespace
-	parserOptions := parser.OpenAPIOptions{
-		SkipValidation: g.config.SkipValidation,
-		ErrorsOnly:     g.config.ErrorsOnly,
-	}
-	
-	spec, err := parser.ParseOpenAPI(g.config.InputSpec, parserOptions)
-	if err != nil {
-		return fmt.Errorf("failed to parse OpenAPI spec: %w", err)
-	}
-	
+// templateData is the model every target template is executed with
+type templateData struct {
+	*parser.ParsedSpec
+	BaseURL    string
+	Contract   string // contract name, empty for an unnamed contract
+	Validate   bool
+	KeyScope   string
+	ZodSchemas []zodDecl
+	GroupedOps map[string][]parser.Operation // operations by namespace, "api" when untagged
+	// CacheEffects lists the queries each mutation changes, by operation id
+	CacheEffects map[string][]cacheEffect
+
+	// Set by the namespaces layout
+	Split      bool     // the output is split into namespace folders
+	Namespace  string   // namespace of the module being rendered, empty at the root
+	RootDir    string   // import path of the root folder, "./" or "../"
+	DTOImports []string // shared DTOs referred to by the DTOs of the namespace
+}
+
+func (g *Generator) templateData(spec *parser.ParsedSpec) templateData {
 	// Group operations by namespace/entity for better organization
 	groupedOps := make(map[string][]parser.Operation)
 	for _, op := range spec.Operations {
@@ -304,62 +357,52 @@ func (g *Generator) generateZustand() error {
 		}
 		groupedOps[namespace] = append(groupedOps[namespace], op)
 	}
-	
-	// Create data for the template
-	templateData := struct {
-		GroupedOps map[string][]parser.Operation
-		Spec       *parser.ParsedSpec
-		BaseURL    string
-	}{
-		GroupedOps: groupedOps,
-		Spec:       spec,
-		BaseURL:    g.config.BaseURL,
-	}
-	
-	// Generate stores content using template
-	content, err := g.execTemplate("stores.tmpl", templateData)
-	if err != nil {
-		return fmt.Errorf("failed to generate stores: %w", err)
+
+	return templateData{
+		ParsedSpec:   spec,
+		BaseURL:      g.config.BaseURL,
+		Contract:     g.config.Contract,
+		Validate:     g.config.Validate,
+		KeyScope:     g.keyScope(),
+		ZodSchemas:   buildZodDecls(spec),
+		GroupedOps:   groupedOps,
+		CacheEffects: buildCacheEffects(spec.Operations),
+		RootDir:      "./",
 	}
-	
-	return g.writeFile("stores.ts", content)
 }
 
-func (g *Generator) generateDTO() error {
-	if g.config.Verbose {
-		fmt.Println("[GEN] Generating TypeScript DTOs...")
-	}
-	
-	// Read the spec to get the schemas
-	parserOptions := parser.OpenAPIOptions{
-		SkipValidation: g.config.SkipValidation,
-		ErrorsOnly:     g.config.ErrorsOnly,
-	}
-	
-	spec, err := parser.ParseOpenAPI(g.config.InputSpec, parserOptions)
+// renderTemplate executes a target template with the given data and returns the result as a string.
+// Partials can be invoked from the template by name, e.g. {{ template "_client" . }}.
+func renderTemplate(name, content string, partials map[string]string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(content)
 	if err != nil {
-		return fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
 	}
-	
-	// Create template data with BaseURL
-	templateData := struct {
-		*parser.ParsedSpec
-		BaseURL string
-	}{
-		ParsedSpec: spec,
-		BaseURL:    g.config.BaseURL,
+	for partialName, partial := range partials {
+		if _, err := tmpl.New(partialName).Parse(partial); err != nil {
+			return "", fmt.Errorf("failed to parse template %s: %w", partialName, err)
+		}
 	}
-	
-	// Generate content using template
-	content, err := g.execTemplate("dto.tmpl", templateData)
-	if err != nil {
-		return fmt.Errorf("failed to generate DTOs: %w", err)
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// keyScope returns the leading query key element that keeps cache entries of
+// different contracts apart, or an empty string for an unnamed contract
+func (g *Generator) keyScope() string {
+	if g.config.Contract == "" {
+		return ""
 	}
-	
-	return g.writeFile("dto.ts", content)
+	return "'" + strings.ReplaceAll(g.config.Contract, "'", "\\'") + "', "
 }
 
-func (g *Generator) writeFile(filename, content string) error {
-	filePath := filepath.Join(g.config.OutputDir, filename)
-	return os.WriteFile(filePath, []byte(content), 0644)
+func capitalize(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
 }
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var goldenFiles = []string{"client.ts", "dto.ts", "queries.ts", "queryClient.ts", "queryKeys.ts", "stores.ts"}
+
+// TestGenerateGolden guards the generated output against regressions and
+// against ordering that depends on Go map iteration
+func TestGenerateGolden(t *testing.T) {
+	for run := 0; run < 3; run++ {
+		outDir := t.TempDir()
+		gen := New(&Config{
+			InputSpec: filepath.Join("testdata", "petstore.yaml"),
+			OutputDir: outDir,
+			BaseURL:   "https://api.example.com",
+		})
+		if err := gen.Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		for _, name := range goldenFiles {
+			got, err := os.ReadFile(filepath.Join(outDir, name))
+			if err != nil {
+				t.Fatalf("failed to read generated %s: %v", name, err)
+			}
+			goldenPath := filepath.Join("testdata", "golden", name+".golden")
+			if *update && run == 0 {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatalf("failed to update %s: %v", goldenPath, err)
+				}
+				continue
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden %s: %v", goldenPath, err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("run %d: %s does not match %s (run with -update to refresh)", run, name, goldenPath)
+			}
+		}
+	}
+}
+
+// TestRenderSpecSharesOneParse renders every target and both layouts from a
+// single parse, which only works while rendering leaves the spec untouched
+func TestRenderSpecSharesOneParse(t *testing.T) {
+	gen := New(&Config{InputSpec: filepath.Join("testdata", "petstore.yaml")})
+	spec, err := gen.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	pristine, err := gen.Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for _, config := range []*Config{
+		{Targets: []string{"zodios", "hooks", "stores"}},
+		{Targets: []string{"fetch", "vue-query", "pinia"}, Layout: LayoutNamespaces},
+		{Targets: []string{"axios", "svelte"}, Validate: true},
+	} {
+		if _, _, err := New(config).RenderSpec(spec); err != nil {
+			t.Fatalf("RenderSpec(%v) error = %v", config.Targets, err)
+		}
+	}
+	if !reflect.DeepEqual(spec, pristine) {
+		t.Errorf("rendering changed the parsed spec")
+	}
+
+	want, timings, err := gen.Render()
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	stages := make(map[string]bool, len(timings))
+	for _, timing := range timings {
+		stages[timing.Stage] = true
+	}
+	if len(timings) == 0 || timings[0].Stage != "parse" {
+		t.Errorf("Render() timings = %v, want the parse first", timings)
+	}
+	for name := range want {
+		if !stages["render "+name] {
+			t.Errorf("Render() timings = %v, missing the render of %s", timings, name)
+		}
+	}
+	got, _, err := gen.RenderSpec(spec)
+	if err != nil {
+		t.Fatalf("RenderSpec() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderSpec() of a shared parse differs from Render()")
+	}
+}
+
+func TestCheckReportsDrift(t *testing.T) {
+	outDir := t.TempDir()
+	gen := New(&Config{
+		InputSpec: filepath.Join("testdata", "petstore.yaml"),
+		OutputDir: outDir,
+		BaseURL:   "https://api.example.com",
+	})
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	drifts, err := gen.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Fatalf("Check() right after Generate() reported %d stale files", len(drifts))
+	}
+
+	dtoPath := filepath.Join(outDir, "dto.ts")
+	if err := os.WriteFile(dtoPath, []byte("// edited by hand\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(outDir, "stores.ts")); err != nil {
+		t.Fatal(err)
+	}
+
+	drifts, err = gen.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(drifts) != 2 {
+		t.Fatalf("Check() reported %d stale files, want 2", len(drifts))
+	}
+	if drifts[0].Path != dtoPath || drifts[0].Missing || !strings.Contains(drifts[0].Diff, "-// edited by hand") {
+		t.Errorf("unexpected drift for dto.ts: %+v", drifts[0])
+	}
+	if !drifts[1].Missing {
+		t.Errorf("stores.ts should be reported as missing: %+v", drifts[1])
+	}
+
+	// Check must never write to the output directory
+	content, err := os.ReadFile(dtoPath)
+	if err != nil || string(content) != "// edited by hand\n" {
+		t.Errorf("Check() modified dto.ts")
+	}
+}
+
+func TestRenderScopesQueryKeysByContract(t *testing.T) {
+	files := renderSpec(t, "petstore.yaml", func(c *Config) { c.Contract = "billing" })
+
+	keys := files["queryKeys.ts"]
+	for _, want := range []string{"all: ['billing'] as const,", "['billing', 'pets', 'listPets', params] as const"} {
+		if !strings.Contains(keys, want) {
+			t.Errorf("query keys should be scoped by contract name, want %q:\n%s", want, keys)
+		}
+	}
+	if strings.Contains(keys, "['pets'") {
+		t.Errorf("found an unscoped query key")
+	}
+
+	stores := renderSpec(t, "petstore.yaml", func(c *Config) {
+		c.Contract = "billing"
+		c.Targets = []string{"fetch", "pinia"}
+	})["stores.ts"]
+	if !strings.Contains(stores, "defineStore('billing/") {
+		t.Errorf("pinia store ids should be scoped by contract name:\n%s", stores)
+	}
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"radas/internal/frontend/parser"
+)
+
+// Output layouts
+const (
+	LayoutSingle     = "single"     // one module per generated file
+	LayoutNamespaces = "namespaces" // one folder per namespace with barrel index.ts files
+)
+
+// rootFiles are rendered once at the root of the namespaces layout
+var rootFiles = map[string]bool{"queryClient.ts": true, "queryKeys.ts": true, "schemas.ts": true}
+
+// sharedFiles are rendered at the root, holding what the namespaces share,
+// and in every namespace; all other files are rendered per namespace
+var sharedFiles = map[string]bool{"client.ts": true, "dto.ts": true}
+
+// renderNamespaces renders the shared modules at the root and the
+// operations and DTOs of every namespace into a folder of their own
+func (g *Generator) renderNamespaces(templates, partials map[string]string, spec *parser.ParsedSpec, timings *Timings) (map[string]string, error) {
+	owners := schemaOwners(spec)
+	shared, owned := splitSchemas(spec.Schemas, owners)
+
+	rootSpec := *spec
+	rootSpec.Schemas = shared
+	root := g.templateData(&rootSpec)
+	// Zod schemas are declared at the root for every namespace
+	root.ZodSchemas = buildZodDecls(spec)
+	root.Split = true
+	root.RootDir = "./"
+
+	files := make(map[string]string)
+	if err := g.renderFiles(files, templates, partials, root, "", timings, func(name string) bool {
+		return rootFiles[name] || sharedFiles[name]
+	}); err != nil {
+		return nil, err
+	}
+
+	folders := make(map[string]string)
+	var modules []string
+	namespaceNames := make([]string, 0, len(root.GroupedOps))
+	for namespace := range root.GroupedOps {
+		namespaceNames = append(namespaceNames, namespace)
+	}
+	sort.Strings(namespaceNames)
+	for _, namespace := range namespaceNames {
+		folder := keyNamespace(namespace)
+		if other, taken := folders[folder]; taken {
+			return nil, fmt.Errorf("namespaces %q and %q both map to the folder %s", other, namespace, folder)
+		}
+		folders[folder] = namespace
+
+		nsSpec := *spec
+		nsSpec.Operations = root.GroupedOps[namespace]
+		nsSpec.Schemas = owned[namespace]
+		data := root
+		data.ParsedSpec = &nsSpec
+		data.GroupedOps = map[string][]parser.Operation{namespace: nsSpec.Operations}
+		data.Namespace = namespace
+		data.RootDir = "../"
+		data.DTOImports = sharedRefs(nsSpec.Schemas, owners)
+
+		if err := g.renderFiles(files, templates, partials, data, folder+"/", timings, func(name string) bool {
+			return !rootFiles[name]
+		}); err != nil {
+			return nil, err
+		}
+		files[folder+"/index.ts"] = barrel(files, folder+"/")
+		modules = append(modules, folder)
+	}
+
+	index := barrel(files, "")
+	for _, folder := range modules {
+		index += "export * as " + folder + " from './" + folder + "';\n"
+	}
+	files["index.ts"] = index
+	return files, nil
+}
+
+// barrel re-exports the modules rendered directly inside dir
+func barrel(files map[string]string, dir string) string {
+	var b strings.Builder
+	b.WriteString("// AUTO-GENERATED barrel\n")
+	for _, name := range sortedFileNames(files) {
+		rest, ok := strings.CutPrefix(name, dir)
+		if !ok || strings.Contains(rest, "/") || rest == "index.ts" || path.Ext(rest) != ".ts" {
+			continue
+		}
+		b.WriteString("export * from './" + strings.TrimSuffix(rest, ".ts") + "';\n")
+	}
+	return b.String()
+}
+
+// schemaOwners assigns every component schema to the one namespace whose
+// operations use it, directly or through other schemas; schemas no operation
+// uses count as used by the namespace of their component prefix (users_User).
+// Schemas used by several namespaces, and recursive ones, are shared ("").
+func schemaOwners(spec *parser.ParsedSpec) map[string]string {
+	defs := make(map[string]*parser.SchemaDef, len(spec.Schemas))
+	for _, schema := range spec.Schemas {
+		defs[schema.Name] = schema.Def
+	}
+
+	// mark records that a namespace uses the schemas, following their
+	// references breadth first
+	users := make(map[string]map[string]bool)
+	mark := func(namespace string, queue []string) {
+		seen := make(map[string]bool)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if users[name] == nil {
+				users[name] = make(map[string]bool)
+			}
+			users[name][namespace] = true
+			queue = append(queue, schemaRefs(defs[name])...)
+		}
+	}
+
+	namespaces := make(map[string]string)
+	for _, op := range spec.Operations {
+		namespace := op.Namespace
+		if namespace == "" {
+			namespace = "api"
+		}
+		namespaces[strings.ToLower(keyNamespace(namespace))] = namespace
+
+		var roots []*parser.SchemaDef
+		for _, param := range op.Parameters {
+			roots = append(roots, param.Def)
+		}
+		if op.RequestBody != nil {
+			roots = append(roots, op.RequestBody.Def)
+			for _, def := range op.RequestBody.Content {
+				roots = append(roots, def)
+			}
+		}
+		for _, resp := range op.Responses {
+			roots = append(roots, resp.Def)
+			for _, def := range resp.Content {
+				roots = append(roots, def)
+			}
+		}
+		var refs []string
+		for _, def := range roots {
+			refs = append(refs, schemaRefs(def)...)
+		}
+		mark(namespace, refs)
+	}
+	for _, schema := range spec.Schemas {
+		if namespace, ok := namespaces[strings.ToLower(keyNamespace(schema.Namespace))]; ok && schema.Namespace != "" && users[schema.Name] == nil {
+			mark(namespace, []string{schema.Name})
+		}
+	}
+
+	// Shared schemas may only refer to shared schemas, so whatever the
+	// remaining unused and the recursive schemas refer to is shared as well;
+	// recursive schemas are typed through the shared DTOs
+	for _, schema := range spec.Schemas {
+		if users[schema.Name] == nil {
+			mark("", []string{schema.Name})
+		}
+	}
+	for _, decl := range buildZodDecls(spec) {
+		if decl.Recursive {
+			mark("", []string{decl.Name})
+		}
+	}
+
+	owners := make(map[string]string, len(spec.Schemas))
+	for _, schema := range spec.Schemas {
+		if used := users[schema.Name]; len(used) == 1 {
+			for namespace := range used {
+				owners[schema.Name] = namespace
+			}
+		} else {
+			owners[schema.Name] = ""
+		}
+	}
+	return owners
+}
+
+// splitSchemas groups the schemas by owning namespace, keeping their order
+func splitSchemas(schemas []parser.Schema, owners map[string]string) ([]parser.Schema, map[string][]parser.Schema) {
+	var shared []parser.Schema
+	owned := make(map[string][]parser.Schema)
+	for _, schema := range schemas {
+		if owner := owners[schema.Name]; owner != "" {
+			owned[owner] = append(owned[owner], schema)
+		} else {
+			shared = append(shared, schema)
+		}
+	}
+	return shared, owned
+}
+
+// sharedRefs lists the shared schemas the given schemas refer to
+func sharedRefs(schemas []parser.Schema, owners map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, schema := range schemas {
+		for _, name := range schemaRefs(schema.Def) {
+			if owner, known := owners[name]; known && owner == "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+
+	"radas/internal/frontend/parser"
+)
+
+// Cache effects of a mutation on a query
+const (
+	cacheInvalidate = "invalidate"
+	cacheUpdate     = "update" // the mutation returns the new data of the query
+	cacheRemove     = "remove" // the mutation deletes the resource of the query
+)
+
+// cacheEffect is what a mutation does to the cached data of one query
+type cacheEffect struct {
+	Query  parser.Operation
+	Action string
+	Params string // key params of the query built from the mutation variables, empty to match every cached variant
+}
+
+// buildCacheEffects relates every mutation to the queries whose cached data
+// it changes. Queries on the same resource, its collection and its
+// sub-resources are derived from the paths, queries of the same tag are the
+// fallback, and x-radas-invalidates replaces both.
+func buildCacheEffects(ops []parser.Operation) map[string][]cacheEffect {
+	var queries []parser.Operation
+	byID := make(map[string]parser.Operation, len(ops))
+	for _, op := range ops {
+		if op.Method == "GET" {
+			queries = append(queries, op)
+			byID[op.ID] = op
+		}
+	}
+
+	effects := make(map[string][]cacheEffect)
+	for _, op := range ops {
+		if op.Method == "GET" {
+			continue
+		}
+		derived := pathEffects(op, queries)
+		if len(derived) == 0 && op.Namespace != "" {
+			for _, query := range queries {
+				if query.Namespace == op.Namespace {
+					derived = append(derived, cacheEffect{Query: query, Action: cacheInvalidate})
+				}
+			}
+		}
+
+		if op.Invalidates != nil {
+			declared := make([]cacheEffect, 0, len(op.Invalidates))
+			for _, id := range op.Invalidates {
+				// Only queries have query keys to invalidate
+				query, ok := byID[id]
+				if !ok {
+					continue
+				}
+				effect := cacheEffect{Query: query, Action: cacheInvalidate}
+				for _, d := range derived {
+					if d.Query.ID == id {
+						effect = d
+					}
+				}
+				declared = append(declared, effect)
+			}
+			derived = declared
+		}
+		if len(derived) > 0 {
+			effects[op.ID] = derived
+		}
+	}
+	return effects
+}
+
+// pathEffects derives the effects of a mutation from the resource paths:
+// the mutation targets an item when its path ends with a parameter or is an
+// action below one, and a collection otherwise
+func pathEffects(op parser.Operation, queries []parser.Operation) []cacheEffect {
+	segments := strings.Split(strings.Trim(op.Path, "/"), "/")
+	n := len(segments)
+	var item, collection []string
+	switch {
+	case isPathParam(segments[n-1]):
+		item, collection = segments, segments[:n-1]
+	case n >= 2 && isPathParam(segments[n-2]) && !hasQueryAt(queries, segments):
+		item, collection = segments[:n-1], segments[:n-2]
+	default:
+		collection = segments
+	}
+
+	var effects []cacheEffect
+	for _, query := range queries {
+		qs := strings.Split(strings.Trim(query.Path, "/"), "/")
+		action := cacheInvalidate
+		switch {
+		case item != nil && samePath(qs, item):
+			if len(item) == n && op.Method == "DELETE" {
+				action = cacheRemove
+			} else if len(item) == n && (op.Method == "PUT" || op.Method == "PATCH") && onlyPathParams(query) && sameResult(op, query) {
+				action = cacheUpdate
+			}
+		case item != nil && len(qs) > len(item) && samePath(qs[:len(item)], item):
+			if len(item) == n && op.Method == "DELETE" {
+				action = cacheRemove
+			}
+		case samePath(qs, collection):
+		default:
+			continue
+		}
+		effects = append(effects, cacheEffect{Query: query, Action: action, Params: keyParams(qs, segments)})
+	}
+	return effects
+}
+
+// keyParams maps the path parameters of a query to the parameters at the
+// same position in the mutation path
+func keyParams(query, mutation []string) string {
+	var fields []string
+	for i, segment := range query {
+		if !isPathParam(segment) || i >= len(mutation) || !isPathParam(mutation[i]) {
+			continue
+		}
+		name := strings.Trim(mutation[i], "{}")
+		value := "variables." + name
+		if !identifierPattern.MatchString(name) {
+			value = "variables[" + jsLiteral(name) + "]"
+		}
+		fields = append(fields, propertyKey(strings.Trim(segment, "{}"))+": "+value)
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// samePath compares path segments, treating all parameters as equal
+func samePath(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !(isPathParam(a[i]) && isPathParam(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasQueryAt(queries []parser.Operation, segments []string) bool {
+	for _, query := range queries {
+		if samePath(strings.Split(strings.Trim(query.Path, "/"), "/"), segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyPathParams reports whether the key of a query is fully known from
+// its path, so its cached data can be replaced
+func onlyPathParams(op parser.Operation) bool {
+	for _, param := range op.Parameters {
+		if param.In != "path" {
+			return false
+		}
+	}
+	return op.RequestBody == nil
+}
+
+// sameResult reports whether a mutation returns the data of a query
+func sameResult(mutation, query parser.Operation) bool {
+	m, ok := successResponse(mutation.Responses)
+	if !ok || m.Def == nil || responseBodyKind(m) != "json" {
+		return false
+	}
+	q, ok := successResponse(query.Responses)
+	return ok && reflect.DeepEqual(m.Def, q.Def)
+}
+
+// cacheUpdates reports whether a mutation replaces the cached data of a query
+func cacheUpdates(effects []cacheEffect) bool {
+	for _, effect := range effects {
+		if effect.Action == cacheUpdate {
+			return true
+		}
+	}
+	return false
+}
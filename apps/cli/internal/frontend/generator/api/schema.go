@@ -0,0 +1,726 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"radas/internal/frontend/parser"
+)
+
+// zodDecl is a Zod schema declaration rendered for a component schema
+type zodDecl struct {
+	Name      string
+	Expr      string
+	Recursive bool // needs an explicit type annotation to break the inference cycle
+}
+
+// zodRenderer renders SchemaDefs as Zod expressions. References to schemas
+// that are not declared yet are wrapped in z.lazy.
+type zodRenderer struct {
+	declared  map[string]bool // nil means every schema is already declared
+	schemas   map[string]*parser.SchemaDef
+	recursive map[string]bool
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// propertyKey quotes a property name when it is not a valid identifier
+func propertyKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// jsLiteral renders a Go value decoded from the spec as a JavaScript literal
+func jsLiteral(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "undefined"
+	}
+	return string(data)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// buildZodDecls renders the Zod declarations for every component schema,
+// ordered so that schemas are declared before the schemas that use them
+func buildZodDecls(spec *parser.ParsedSpec) []zodDecl {
+	recursive := recursiveSchemas(spec.Schemas)
+	r := &zodRenderer{
+		declared:  make(map[string]bool),
+		schemas:   make(map[string]*parser.SchemaDef, len(spec.Schemas)),
+		recursive: recursive,
+	}
+	for _, s := range spec.Schemas {
+		r.schemas[s.Name] = s.Def
+	}
+
+	decls := make([]zodDecl, 0, len(spec.Schemas))
+	for _, s := range orderSchemas(spec.Schemas) {
+		decls = append(decls, zodDecl{
+			Name:      s.Name,
+			Expr:      r.render(s.Def),
+			Recursive: recursive[s.Name],
+		})
+		r.declared[s.Name] = true
+	}
+	return decls
+}
+
+// orderSchemas sorts schemas topologically by their references, breaking
+// ties and cycles by name so the order is stable across runs
+func orderSchemas(schemas []parser.Schema) []parser.Schema {
+	byName := make(map[string]parser.Schema, len(schemas))
+	names := make([]string, 0, len(schemas))
+	for _, s := range schemas {
+		if _, dup := byName[s.Name]; !dup {
+			names = append(names, s.Name)
+		}
+		byName[s.Name] = s
+	}
+	sort.Strings(names)
+
+	ordered := make([]parser.Schema, 0, len(schemas))
+	visited := make(map[string]bool, len(schemas))
+	var visit func(name string)
+	visit = func(name string) {
+		s, ok := byName[name]
+		if !ok || visited[name] {
+			return
+		}
+		visited[name] = true
+		deps := schemaRefs(s.Def)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			visit(dep)
+		}
+		ordered = append(ordered, s)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return ordered
+}
+
+// recursiveSchemas returns the names of schemas that reference themselves,
+// directly or through other schemas
+func recursiveSchemas(schemas []parser.Schema) map[string]bool {
+	graph := make(map[string][]string, len(schemas))
+	for _, s := range schemas {
+		graph[s.Name] = schemaRefs(s.Def)
+	}
+
+	result := make(map[string]bool)
+	for _, s := range schemas {
+		seen := make(map[string]bool)
+		stack := append([]string{}, graph[s.Name]...)
+		for len(stack) > 0 {
+			name := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if name == s.Name {
+				result[s.Name] = true
+				break
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			stack = append(stack, graph[name]...)
+		}
+	}
+	return result
+}
+
+// schemaRefs lists the schema names referenced anywhere inside def
+func schemaRefs(def *parser.SchemaDef) []string {
+	var refs []string
+	var walk func(d *parser.SchemaDef)
+	walk = func(d *parser.SchemaDef) {
+		if d == nil {
+			return
+		}
+		if d.Ref != "" {
+			refs = append(refs, d.Ref)
+			return
+		}
+		walk(d.Items)
+		walk(d.AdditionalProperties)
+		for _, p := range d.Properties {
+			walk(p.Def)
+		}
+		for _, members := range [][]*parser.SchemaDef{d.AllOf, d.OneOf, d.AnyOf} {
+			for _, m := range members {
+				walk(m)
+			}
+		}
+	}
+	walk(def)
+	return refs
+}
+
+func (r *zodRenderer) render(def *parser.SchemaDef) string {
+	if def == nil {
+		return "z.any()"
+	}
+	if def.Ref != "" {
+		if r.declared != nil && !r.declared[def.Ref] {
+			return fmt.Sprintf("z.lazy(() => %sSchema)", def.Ref)
+		}
+		return def.Ref + "Schema"
+	}
+
+	expr := r.renderBase(def)
+	if def.Nullable && def.Type != "null" {
+		expr += ".nullable()"
+	}
+	if def.Description != "" {
+		expr += fmt.Sprintf(".describe(%s)", strconv.Quote(def.Description))
+	}
+	if def.Default != nil {
+		expr += fmt.Sprintf(".default(%s)", jsLiteral(def.Default))
+	}
+	return expr
+}
+
+func (r *zodRenderer) renderBase(def *parser.SchemaDef) string {
+	if isComposed(def) {
+		return r.renderComposition(def)
+	}
+	if len(def.Enum) > 0 {
+		return zodEnum(def.Enum)
+	}
+
+	switch def.Type {
+	case "string":
+		if isBinary(def) {
+			return "z.instanceof(Blob)"
+		}
+		return "z.string()" + zodStringChecks(def)
+	case "integer":
+		return "z.number().int()" + zodNumberChecks(def)
+	case "number":
+		return "z.number()" + zodNumberChecks(def)
+	case "boolean":
+		return "z.boolean()"
+	case "null":
+		return "z.null()"
+	case "array":
+		expr := fmt.Sprintf("z.array(%s)", r.render(def.Items))
+		if def.MinItems != nil {
+			expr += fmt.Sprintf(".min(%d)", *def.MinItems)
+		}
+		if def.MaxItems != nil {
+			expr += fmt.Sprintf(".max(%d)", *def.MaxItems)
+		}
+		return expr
+	case "object":
+		return r.renderObject(def)
+	default:
+		return "z.any()"
+	}
+}
+
+func (r *zodRenderer) renderObject(def *parser.SchemaDef) string {
+	if len(def.Properties) == 0 {
+		if def.AdditionalProperties != nil {
+			return fmt.Sprintf("z.record(z.string(), %s)", r.render(def.AdditionalProperties))
+		}
+		if def.Strict {
+			return "z.object({}).strict()"
+		}
+		return "z.record(z.string(), z.any())"
+	}
+
+	props := make([]string, 0, len(def.Properties))
+	for _, p := range def.Properties {
+		expr := r.render(p.Def)
+		if !p.Required && (p.Def == nil || p.Def.Default == nil) {
+			expr += ".optional()"
+		}
+		props = append(props, fmt.Sprintf("%s: %s", propertyKey(p.Name), expr))
+	}
+
+	expr := fmt.Sprintf("z.object({ %s })", strings.Join(props, ", "))
+	switch {
+	case def.AdditionalProperties != nil:
+		expr += fmt.Sprintf(".catchall(%s)", r.render(def.AdditionalProperties))
+	case def.Strict:
+		expr += ".strict()"
+	default:
+		expr += ".passthrough()"
+	}
+	return expr
+}
+
+// isComposed reports whether def uses allOf, oneOf or anyOf
+func isComposed(def *parser.SchemaDef) bool {
+	return len(def.AllOf) > 0 || len(def.OneOf) > 0 || len(def.AnyOf) > 0
+}
+
+// ownPart returns the keywords declared next to a composition (e.g. the
+// properties added to an allOf), or nil if there are none
+func ownPart(def *parser.SchemaDef) *parser.SchemaDef {
+	own := *def
+	own.AllOf, own.OneOf, own.AnyOf, own.Discriminator = nil, nil, nil, nil
+	own.Nullable, own.Default, own.Description = false, nil, ""
+	if own.Type == "" && len(own.Enum) == 0 {
+		return nil
+	}
+	if own.Type == "object" && len(own.Properties) == 0 && own.AdditionalProperties == nil && !own.Strict {
+		return nil
+	}
+	return &own
+}
+
+// isZodObject reports whether def renders to a z.object, which is required
+// for .merge and z.discriminatedUnion
+func (r *zodRenderer) isZodObject(def *parser.SchemaDef) bool {
+	if def == nil {
+		return false
+	}
+	if def.Ref != "" {
+		target, ok := r.schemas[def.Ref]
+		if !ok || r.recursive[def.Ref] || (r.declared != nil && !r.declared[def.Ref]) {
+			return false
+		}
+		return r.isZodObject(target)
+	}
+	if def.Nullable || def.Default != nil || len(def.Enum) > 0 || len(def.OneOf) > 0 || len(def.AnyOf) > 0 {
+		return false
+	}
+	if len(def.AllOf) > 0 {
+		for _, m := range def.AllOf {
+			if !r.isZodObject(m) {
+				return false
+			}
+		}
+		own := ownPart(def)
+		return own == nil || r.isZodObject(own)
+	}
+	return def.Type == "object" && (len(def.Properties) > 0 || def.Strict)
+}
+
+func (r *zodRenderer) renderComposition(def *parser.SchemaDef) string {
+	var parts []string
+
+	allOf := append([]*parser.SchemaDef{}, def.AllOf...)
+	if own := ownPart(def); own != nil {
+		allOf = append(allOf, own)
+	}
+	if len(allOf) > 0 {
+		parts = append(parts, r.renderAllOf(allOf))
+	}
+	if len(def.OneOf) > 0 {
+		parts = append(parts, r.renderUnion(def.OneOf, def.Discriminator))
+	}
+	if len(def.AnyOf) > 0 {
+		parts = append(parts, r.renderUnion(def.AnyOf, def.Discriminator))
+	}
+	return zodIntersection(parts)
+}
+
+// renderAllOf merges object members with .merge so the result stays a
+// z.object, and falls back to z.intersection otherwise
+func (r *zodRenderer) renderAllOf(members []*parser.SchemaDef) string {
+	if len(members) == 1 {
+		return r.render(members[0])
+	}
+
+	mergeable := true
+	for _, m := range members {
+		if !r.isZodObject(m) {
+			mergeable = false
+			break
+		}
+	}
+
+	rendered := make([]string, 0, len(members))
+	for _, m := range members {
+		rendered = append(rendered, r.render(m))
+	}
+	if !mergeable {
+		return zodIntersection(rendered)
+	}
+
+	expr := rendered[0]
+	for _, m := range rendered[1:] {
+		expr += fmt.Sprintf(".merge(%s)", m)
+	}
+	return expr
+}
+
+func zodIntersection(parts []string) string {
+	expr := parts[0]
+	for _, p := range parts[1:] {
+		expr = fmt.Sprintf("z.intersection(%s, %s)", expr, p)
+	}
+	return expr
+}
+
+// renderUnion emits z.discriminatedUnion when every variant is an object
+// with a known discriminator value, and z.union otherwise
+func (r *zodRenderer) renderUnion(members []*parser.SchemaDef, d *parser.Discriminator) string {
+	if len(members) == 1 {
+		return r.render(members[0])
+	}
+
+	if d != nil {
+		if variants, ok := r.discriminatedVariants(members, d); ok {
+			return fmt.Sprintf("z.discriminatedUnion(%s, [%s])", strconv.Quote(d.PropertyName), strings.Join(variants, ", "))
+		}
+	}
+
+	rendered := make([]string, 0, len(members))
+	for _, m := range members {
+		rendered = append(rendered, r.render(m))
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(rendered, ", "))
+}
+
+func (r *zodRenderer) discriminatedVariants(members []*parser.SchemaDef, d *parser.Discriminator) ([]string, bool) {
+	variants := make([]string, 0, len(members))
+	for _, m := range members {
+		if !r.isZodObject(m) {
+			return nil, false
+		}
+		expr := r.render(m)
+		if m.Ref == "" {
+			// Inline variants must pin the discriminator themselves
+			prop := findProperty(m, d.PropertyName)
+			if prop == nil || len(prop.Def.Enum) == 0 {
+				return nil, false
+			}
+			variants = append(variants, expr)
+			continue
+		}
+		values := discriminatorValues(d, m.Ref)
+		literal := zodEnum(values)
+		variants = append(variants, fmt.Sprintf("%s.extend({ %s: %s })", expr, propertyKey(d.PropertyName), literal))
+	}
+	return variants, true
+}
+
+// discriminatorValues returns the discriminator values that select the
+// schema, defaulting to the schema name as the spec prescribes
+func discriminatorValues(d *parser.Discriminator, schemaName string) []interface{} {
+	var values []interface{}
+	for _, value := range sortedMapKeys(d.Mapping) {
+		if d.Mapping[value] == schemaName {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		values = append(values, schemaName)
+	}
+	return values
+}
+
+func sortedMapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func findProperty(def *parser.SchemaDef, name string) *parser.Property {
+	for i := range def.Properties {
+		if def.Properties[i].Name == name {
+			return &def.Properties[i]
+		}
+	}
+	return nil
+}
+
+func zodEnum(values []interface{}) string {
+	allStrings := true
+	for _, v := range values {
+		if _, ok := v.(string); !ok {
+			allStrings = false
+			break
+		}
+	}
+	if allStrings && len(values) > 1 {
+		quoted := make([]string, 0, len(values))
+		for _, v := range values {
+			quoted = append(quoted, jsLiteral(v))
+		}
+		return fmt.Sprintf("z.enum([%s])", strings.Join(quoted, ", "))
+	}
+
+	literals := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == nil {
+			literals = append(literals, "z.null()")
+			continue
+		}
+		literals = append(literals, fmt.Sprintf("z.literal(%s)", jsLiteral(v)))
+	}
+	if len(literals) == 1 {
+		return literals[0]
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(literals, ", "))
+}
+
+// isBinary reports whether a string schema carries file contents, as in
+// multipart uploads and octet-stream bodies
+func isBinary(def *parser.SchemaDef) bool {
+	return def.Type == "string" && def.Format == "binary"
+}
+
+func zodStringChecks(def *parser.SchemaDef) string {
+	var checks strings.Builder
+	switch def.Format {
+	case "email":
+		checks.WriteString(".email()")
+	case "uuid":
+		checks.WriteString(".uuid()")
+	case "uri", "url":
+		checks.WriteString(".url()")
+	case "date-time":
+		checks.WriteString(".datetime({ offset: true })")
+	}
+	if def.MinLength != nil {
+		checks.WriteString(fmt.Sprintf(".min(%d)", *def.MinLength))
+	}
+	if def.MaxLength != nil {
+		checks.WriteString(fmt.Sprintf(".max(%d)", *def.MaxLength))
+	}
+	if def.Pattern != "" {
+		checks.WriteString(fmt.Sprintf(".regex(new RegExp(%s))", strconv.Quote(def.Pattern)))
+	}
+	return checks.String()
+}
+
+func zodNumberChecks(def *parser.SchemaDef) string {
+	var checks strings.Builder
+	if def.Minimum != nil {
+		if def.ExclusiveMinimum {
+			checks.WriteString(fmt.Sprintf(".gt(%s)", formatNumber(*def.Minimum)))
+		} else {
+			checks.WriteString(fmt.Sprintf(".min(%s)", formatNumber(*def.Minimum)))
+		}
+	}
+	if def.Maximum != nil {
+		if def.ExclusiveMaximum {
+			checks.WriteString(fmt.Sprintf(".lt(%s)", formatNumber(*def.Maximum)))
+		} else {
+			checks.WriteString(fmt.Sprintf(".max(%s)", formatNumber(*def.Maximum)))
+		}
+	}
+	return checks.String()
+}
+
+// zodType renders a schema definition as a Zod expression for use after all
+// component schemas have been declared
+func zodType(def *parser.SchemaDef) string {
+	return (&zodRenderer{}).render(def)
+}
+
+// tsType renders a schema definition as a TypeScript type inside dto.ts
+func tsType(def *parser.SchemaDef) string {
+	return renderTSType(def, "")
+}
+
+// dtoType renders a schema definition as a TypeScript type for modules that
+// import the DTOs as `DTO`
+func dtoType(def *parser.SchemaDef) string {
+	return renderTSType(def, "DTO.")
+}
+
+func renderTSType(def *parser.SchemaDef, prefix string) string {
+	if def == nil {
+		return "any"
+	}
+	if def.Ref != "" {
+		return prefix + def.Ref
+	}
+
+	ts := renderTSBase(def, prefix)
+	if def.Nullable && def.Type != "null" {
+		ts += " | null"
+	}
+	return ts
+}
+
+func renderTSBase(def *parser.SchemaDef, prefix string) string {
+	if isComposed(def) {
+		return renderTSComposition(def, prefix)
+	}
+	if len(def.Enum) > 0 {
+		values := make([]string, 0, len(def.Enum))
+		for _, v := range def.Enum {
+			values = append(values, jsLiteral(v))
+		}
+		return strings.Join(values, " | ")
+	}
+
+	switch def.Type {
+	case "string":
+		if isBinary(def) {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	case "array":
+		item := renderTSType(def.Items, prefix)
+		if strings.Contains(item, " ") {
+			return fmt.Sprintf("Array<%s>", item)
+		}
+		return item + "[]"
+	case "object":
+		if len(def.Properties) == 0 {
+			if def.AdditionalProperties != nil {
+				return fmt.Sprintf("Record<string, %s>", renderTSType(def.AdditionalProperties, prefix))
+			}
+			return "Record<string, any>"
+		}
+		members := make([]string, 0, len(def.Properties)+1)
+		for _, p := range def.Properties {
+			members = append(members, tsMember(p, prefix))
+		}
+		if def.AdditionalProperties != nil {
+			members = append(members, "[key: string]: unknown")
+		}
+		return fmt.Sprintf("{ %s }", strings.Join(members, "; "))
+	default:
+		return "any"
+	}
+}
+
+func renderTSComposition(def *parser.SchemaDef, prefix string) string {
+	var parts []string
+	for _, m := range def.AllOf {
+		parts = append(parts, tsParens(renderTSType(m, prefix)))
+	}
+	if own := ownPart(def); own != nil {
+		parts = append(parts, tsParens(renderTSType(own, prefix)))
+	}
+	for _, members := range [][]*parser.SchemaDef{def.OneOf, def.AnyOf} {
+		if len(members) == 0 {
+			continue
+		}
+		variants := make([]string, 0, len(members))
+		for _, m := range members {
+			variant := renderTSType(m, prefix)
+			if def.Discriminator != nil && m.Ref != "" {
+				values := discriminatorValues(def.Discriminator, m.Ref)
+				literals := make([]string, 0, len(values))
+				for _, v := range values {
+					literals = append(literals, jsLiteral(v))
+				}
+				variant = fmt.Sprintf("%s & { %s: %s }", variant, propertyKey(def.Discriminator.PropertyName), strings.Join(literals, " | "))
+			}
+			variants = append(variants, tsParens(variant))
+		}
+		union := strings.Join(variants, " | ")
+		if len(variants) > 1 {
+			union = "(" + union + ")"
+		}
+		parts = append(parts, union)
+	}
+	if len(parts) == 1 {
+		return strings.TrimSuffix(strings.TrimPrefix(parts[0], "("), ")")
+	}
+	return strings.Join(parts, " & ")
+}
+
+// tsParens wraps top-level union and intersection types so they can be
+// combined with other types
+func tsParens(ts string) string {
+	depth := 0
+	for i := 0; i < len(ts); i++ {
+		switch ts[i] {
+		case '{', '(', '<', '[':
+			depth++
+		case '}', ')', '>', ']':
+			depth--
+		case '|', '&':
+			if depth == 0 {
+				return "(" + ts + ")"
+			}
+		}
+	}
+	return ts
+}
+
+func tsMember(p parser.Property, prefix string) string {
+	optional := "?"
+	if p.Required {
+		optional = ""
+	}
+	return fmt.Sprintf("%s%s: %s", propertyKey(p.Name), optional, renderTSType(p.Def, prefix))
+}
+
+// tsDoc renders a JSDoc comment for a schema definition
+func tsDoc(def *parser.SchemaDef, indent string) string {
+	if def == nil {
+		return ""
+	}
+	var lines []string
+	if def.Description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(def.Description), "\n") {
+			lines = append(lines, strings.ReplaceAll(line, "*/", "*\\/"))
+		}
+	}
+	if def.Format != "" {
+		lines = append(lines, "@format "+def.Format)
+	}
+	if def.Default != nil {
+		lines = append(lines, "@default "+jsLiteral(def.Default))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	if len(lines) == 1 {
+		return fmt.Sprintf("%s/** %s */\n", indent, lines[0])
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(fmt.Sprintf("%s * %s", indent, line), " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+// tsDeclaration renders the dto.ts declaration of a component schema: an
+// interface for objects with known properties and a type alias otherwise
+func tsDeclaration(s parser.Schema) string {
+	var b strings.Builder
+	doc := &parser.SchemaDef{Description: s.Description}
+	if s.Def != nil && s.Def.Ref == "" {
+		doc = s.Def
+	}
+	b.WriteString(tsDoc(doc, ""))
+
+	def := s.Def
+	if def != nil && def.Ref == "" && def.Type == "object" && len(def.Properties) > 0 && !def.Nullable && len(def.Enum) == 0 && !isComposed(def) {
+		b.WriteString(fmt.Sprintf("export interface %s {\n", s.Name))
+		for _, p := range def.Properties {
+			b.WriteString(tsDoc(p.Def, "  "))
+			b.WriteString("  " + tsMember(p, "") + ";\n")
+		}
+		if def.AdditionalProperties != nil {
+			b.WriteString("  [key: string]: unknown;\n")
+		}
+		b.WriteString("}\n")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("export type %s = %s;\n", s.Name, tsType(def)))
+	return b.String()
+}
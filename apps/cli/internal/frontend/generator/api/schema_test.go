@@ -0,0 +1,88 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"radas/internal/frontend/parser"
+)
+
+func objectDef(props ...string) *parser.SchemaDef {
+	def := &parser.SchemaDef{Type: "object"}
+	for _, name := range props {
+		def.Properties = append(def.Properties, parser.Property{
+			Name:     name,
+			Required: true,
+			Def:      &parser.SchemaDef{Type: "string"},
+		})
+	}
+	return def
+}
+
+func TestBuildZodDeclsComposition(t *testing.T) {
+	spec := &parser.ParsedSpec{Schemas: []parser.Schema{
+		{Name: "Base", Def: objectDef("id")},
+		{Name: "Cat", Def: &parser.SchemaDef{AllOf: []*parser.SchemaDef{{Ref: "Base"}, objectDef("meows")}}},
+		{Name: "Dog", Def: &parser.SchemaDef{AllOf: []*parser.SchemaDef{{Ref: "Base"}, objectDef("barks")}}},
+		{Name: "Pet", Def: &parser.SchemaDef{
+			OneOf:         []*parser.SchemaDef{{Ref: "Cat"}, {Ref: "Dog"}},
+			Discriminator: &parser.Discriminator{PropertyName: "kind", Mapping: map[string]string{"cat": "Cat"}},
+		}},
+		{Name: "Shape", Def: &parser.SchemaDef{OneOf: []*parser.SchemaDef{{Type: "string"}, {Ref: "Base"}}}},
+	}}
+
+	exprs := make(map[string]string)
+	for _, decl := range buildZodDecls(spec) {
+		exprs[decl.Name] = decl.Expr
+	}
+
+	if want := "BaseSchema.merge("; !strings.HasPrefix(exprs["Cat"], want) {
+		t.Errorf("Cat = %s, want prefix %s", exprs["Cat"], want)
+	}
+	wantPet := `z.discriminatedUnion("kind", [CatSchema.extend({ kind: z.literal("cat") }), DogSchema.extend({ kind: z.literal("Dog") })])`
+	if exprs["Pet"] != wantPet {
+		t.Errorf("Pet = %s, want %s", exprs["Pet"], wantPet)
+	}
+	if want := "z.union([z.string(), BaseSchema])"; exprs["Shape"] != want {
+		t.Errorf("Shape = %s, want %s", exprs["Shape"], want)
+	}
+}
+
+func TestTSTypeComposition(t *testing.T) {
+	def := &parser.SchemaDef{
+		OneOf:         []*parser.SchemaDef{{Ref: "Cat"}, {Ref: "Dog"}},
+		Discriminator: &parser.Discriminator{PropertyName: "kind", Mapping: map[string]string{"cat": "Cat", "dog": "Dog"}},
+	}
+	want := `(Cat & { kind: "cat" }) | (Dog & { kind: "dog" })`
+	if got := tsType(def); got != want {
+		t.Errorf("tsType() = %s, want %s", got, want)
+	}
+
+	merged := &parser.SchemaDef{AllOf: []*parser.SchemaDef{{Ref: "Base"}, objectDef("name")}}
+	if got, want := dtoType(merged), "DTO.Base & { name: string }"; got != want {
+		t.Errorf("dtoType() = %s, want %s", got, want)
+	}
+}
+
+func TestResponseTypes(t *testing.T) {
+	responses := map[string]parser.Response{
+		"201":     {Def: &parser.SchemaDef{Ref: "User"}},
+		"200":     {Def: &parser.SchemaDef{Type: "array", Items: &parser.SchemaDef{Ref: "User"}}},
+		"404":     {Def: &parser.SchemaDef{Ref: "Problem"}},
+		"default": {},
+	}
+
+	if got, want := returnTypeTemplate(responses), "DTO.User[]"; got != want {
+		t.Errorf("returnTypeTemplate() = %s, want %s", got, want)
+	}
+	if got, want := getSuccessResponseSchema(responses), "z.array(UserSchema)"; got != want {
+		t.Errorf("getSuccessResponseSchema() = %s, want %s", got, want)
+	}
+	want := "ApiError<404, DTO.Problem> | ApiError<number, unknown> | ValidationError"
+	if got := errorType(responses); got != want {
+		t.Errorf("errorType() = %s, want %s", got, want)
+	}
+	if got := returnTypeTemplate(map[string]parser.Response{"204": {}}); got != "void" {
+		t.Errorf("returnTypeTemplate(204) = %s, want void", got)
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// builtinTargets holds the targets shipped with the CLI, one directory each
+//
+//go:embed all:targets
+var builtinTargets embed.FS
+
+// commonTarget is rendered for every contract regardless of the selected targets
+const commonTarget = "common"
+
+// defaultClientTarget is added when no selected target renders client.ts,
+// since hooks and stores import the API client from ./client
+const defaultClientTarget = "zodios"
+
+// DefaultTargets are generated when a contract does not select any
+var DefaultTargets = []string{"zodios", "hooks", "stores"}
+
+// Target is a named set of templates; each foo.ts.tmpl renders foo.ts while
+// templates named _foo.tmpl are partials that every template can invoke
+type Target struct {
+	Name      string
+	Templates map[string]string // output file name -> template source
+}
+
+// Registry resolves target names to templates, layering user-supplied
+// targets over the built-in ones
+type Registry struct {
+	targets map[string]*Target
+}
+
+// NewRegistry loads the built-in targets and, when overrideDir is set, every
+// target directory inside it. A user directory named like a built-in target
+// replaces just the templates it contains, so single files can be customized.
+func NewRegistry(overrideDir string) (*Registry, error) {
+	r := &Registry{targets: make(map[string]*Target)}
+
+	root, err := fs.Sub(builtinTargets, "targets")
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(root); err != nil {
+		return nil, fmt.Errorf("failed to load built-in targets: %w", err)
+	}
+
+	if overrideDir != "" {
+		if err := r.load(os.DirFS(overrideDir)); err != nil {
+			return nil, fmt.Errorf("failed to load templates from %s: %w", overrideDir, err)
+		}
+	}
+	return r, nil
+}
+
+func (r *Registry) load(root fs.FS) error {
+	entries, err := fs.ReadDir(root, ".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		files, err := fs.Glob(root, entry.Name()+"/*.tmpl")
+		if err != nil {
+			return err
+		}
+		target := r.targets[entry.Name()]
+		if target == nil {
+			target = &Target{Name: entry.Name(), Templates: make(map[string]string)}
+			r.targets[entry.Name()] = target
+		}
+		for _, file := range files {
+			content, err := fs.ReadFile(root, file)
+			if err != nil {
+				return err
+			}
+			target.Templates[strings.TrimSuffix(filepath.Base(file), ".tmpl")] = string(content)
+		}
+	}
+	return nil
+}
+
+// isPartial reports whether a template is only included by other templates
+func isPartial(name string) bool {
+	return strings.HasPrefix(name, "_")
+}
+
+// Names lists the selectable targets
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.targets))
+	for name := range r.targets {
+		if name != commonTarget {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Target returns the named target
+func (r *Registry) Target(name string) (*Target, error) {
+	target, ok := r.targets[name]
+	if !ok || name == commonTarget {
+		return nil, fmt.Errorf("unknown target %q (available: %s)", name, strings.Join(r.Names(), ", "))
+	}
+	return target, nil
+}
+
+// Templates collects the templates of the selected targets keyed by output
+// file name. Files of the common target can be replaced by a selected target,
+// but two selected targets may not render the same file.
+func (r *Registry) Templates(names []string) (map[string]string, error) {
+	if len(names) == 0 {
+		names = DefaultTargets
+	}
+
+	result := make(map[string]string)
+	owners := make(map[string]string)
+	for _, name := range names {
+		target, err := r.Target(name)
+		if err != nil {
+			return nil, err
+		}
+		for file, content := range target.Templates {
+			if owner, taken := owners[file]; taken && owner != name {
+				return nil, fmt.Errorf("targets %s and %s both generate %s", owner, name, file)
+			}
+			owners[file] = name
+			result[file] = content
+		}
+	}
+
+	if _, ok := result["client.ts"]; !ok {
+		client, err := r.Target(defaultClientTarget)
+		if err != nil {
+			return nil, err
+		}
+		for file, content := range client.Templates {
+			if _, taken := result[file]; !taken {
+				result[file] = content
+			}
+		}
+	}
+
+	if common, ok := r.targets[commonTarget]; ok {
+		for file, content := range common.Templates {
+			if _, taken := result[file]; !taken {
+				result[file] = content
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,319 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// renderSpec renders a spec from testdata with the default targets, changed
+// by mutate if set
+func renderSpec(t *testing.T, spec string, mutate func(*Config)) map[string]string {
+	t.Helper()
+	config := &Config{InputSpec: filepath.Join("testdata", spec), OutputDir: t.TempDir()}
+	if mutate != nil {
+		mutate(config)
+	}
+	files, _, err := New(config).Render()
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	return files
+}
+
+// withTargets renders the given targets instead of the defaults
+func withTargets(targets ...string) func(*Config) {
+	return func(c *Config) { c.Targets = targets }
+}
+
+func TestRenderVueTargets(t *testing.T) {
+	files := renderSpec(t, "petstore.yaml", withTargets("fetch", "vue-query", "pinia"))
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if got := strings.Join(names, ","); got != "client.ts,dto.ts,queries.ts,queryKeys.ts,stores.ts" {
+		t.Fatalf("rendered files = %s", got)
+	}
+	if strings.Contains(files["client.ts"], "axios") {
+		t.Errorf("fetch client should not depend on axios")
+	}
+	if !strings.Contains(files["queries.ts"], "queryKey: computed(() => queryKeys.pets.listPets(toValue(params))),") {
+		t.Errorf("vue composables should use the shared query keys:\n%s", files["queries.ts"])
+	}
+}
+
+func TestRegistryOverrides(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"hooks/queries.ts.tmpl":   "// custom {{ len .Operations }}\n",
+		"angular/service.ts.tmpl": "// service\n",
+		"angular/stores.ts.tmpl":  "// stores\n",
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry, err := NewRegistry(dir)
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	templates, err := registry.Templates([]string{"hooks"})
+	if err != nil {
+		t.Fatalf("Templates() error = %v", err)
+	}
+	if templates["queries.ts"] != "// custom {{ len .Operations }}\n" {
+		t.Errorf("user template should replace the built-in queries.ts")
+	}
+	if _, ok := templates["queryClient.ts"]; !ok {
+		t.Errorf("built-in templates not overridden should be kept")
+	}
+	if _, ok := templates["client.ts"]; !ok {
+		t.Errorf("the default client should be added when no target renders client.ts")
+	}
+
+	if _, err := registry.Templates([]string{"stores", "angular"}); err == nil || !strings.Contains(err.Error(), "both generate stores.ts") {
+		t.Errorf("expected a collision error, got %v", err)
+	}
+	if _, err := registry.Templates([]string{"react"}); err == nil || !strings.Contains(err.Error(), "available: angular, axios, fetch") {
+		t.Errorf("expected an unknown target error listing targets, got %v", err)
+	}
+}
+
+func TestRenderClientTargets(t *testing.T) {
+	plain := renderSpec(t, "petstore.yaml", withTargets("fetch"))
+	if _, ok := plain["schemas.ts"]; ok {
+		t.Errorf("schemas.ts should only be generated with runtime validation")
+	}
+	if strings.Contains(plain["client.ts"], "zod") {
+		t.Errorf("client without runtime validation should not import zod")
+	}
+	if !strings.Contains(plain["client.ts"], "{ name: 'limit', in: 'query', style: 'form', explode: true, value: params['limit'] }") {
+		t.Errorf("query parameters should carry their serialization rules:\n%s", plain["client.ts"])
+	}
+	if !strings.Contains(plain["client.ts"], "export const petsApi = {") {
+		t.Errorf("operations should be grouped per namespace")
+	}
+
+	validated := renderSpec(t, "petstore.yaml", func(c *Config) {
+		c.Targets = []string{"axios"}
+		c.Validate = true
+	})
+	if !strings.Contains(validated["schemas.ts"], "export const PetSchema") {
+		t.Errorf("schemas.ts should declare the Zod schemas")
+	}
+	client := validated["client.ts"]
+	for _, want := range []string{"import axios from 'axios';", "requestSchema: NewPetSchema,", "responseSchema: z.array(PetSchema),"} {
+		if !strings.Contains(client, want) {
+			t.Errorf("axios client should contain %q", want)
+		}
+	}
+}
+
+func TestRenderBodies(t *testing.T) {
+	fetchClient := renderSpec(t, "files.yaml", withTargets("fetch"))["client.ts"]
+	for _, want := range []string{
+		"body: { description?: string; file: Blob };",
+		"contentType: 'multipart/form-data',",
+		"contentType: 'application/x-www-form-urlencoded',",
+		"): Promise<Blob> =>",
+		"accept: 'application/octet-stream',\n    responseType: 'blob',",
+		"): Promise<string> =>",
+		"accept: 'text/csv',\n    responseType: 'text',",
+	} {
+		if !strings.Contains(fetchClient, want) {
+			t.Errorf("fetch client should contain %q:\n%s", want, fetchClient)
+		}
+	}
+
+	zodiosClient := renderSpec(t, "files.yaml", withTargets("zodios"))["client.ts"]
+	for _, want := range []string{
+		"const toFormData = ",
+		"data: encodeBody(params.body, 'multipart/form-data'),",
+		"Accept: 'text/csv' },\n        responseType: 'text',",
+	} {
+		if !strings.Contains(zodiosClient, want) {
+			t.Errorf("zodios client should contain %q:\n%s", want, zodiosClient)
+		}
+	}
+}
+
+func TestRenderSecurity(t *testing.T) {
+	fetchClient := renderSpec(t, "secured.yaml", withTargets("fetch"))["client.ts"]
+	for _, want := range []string{
+		"bearerAuth?: Credential<string>;",
+		"basicAuth?: Credential<BasicCredentials>;",
+		"apiKey: { type: 'apiKey', in: 'query', name: \"api_key\" },",
+		"security: [{ bearerAuth: [] }],",
+		"security: [],",
+		"security: [{ apiKey: [] }, { oauth: [\"reports:read\"] }, { basicAuth: [] }],",
+		"context.url = await authorize(context.url, context.headers, operation.security);",
+	} {
+		if !strings.Contains(fetchClient, want) {
+			t.Errorf("fetch client should contain %q:\n%s", want, fetchClient)
+		}
+	}
+	if strings.Contains(fetchClient, "context.headers['Authorization'] = 'Bearer ' + token;") {
+		t.Errorf("the token should only be sent to operations that require it")
+	}
+
+	files := renderSpec(t, "secured.yaml", withTargets("zodios", "hooks"))
+	if !strings.Contains(files["client.ts"], "url = await authorize(url, headers, [{ bearerAuth: [] }]);") {
+		t.Errorf("zodios operations should authorize their requests:\n%s", files["client.ts"])
+	}
+	if !strings.Contains(files["queryClient.ts"], "export const setAuthProviders = (providers: AuthProviders) => {") {
+		t.Errorf("queryClient.ts should offer the auth providers:\n%s", files["queryClient.ts"])
+	}
+}
+
+func TestRenderPagination(t *testing.T) {
+	files := renderSpec(t, "paginated.yaml", withTargets("fetch", "hooks", "stores"))
+
+	for file, wants := range map[string][]string{
+		"queries.ts": {
+			"export function useListEventsInfinite(",
+			"queryKey: queryKeys.events.listEventsInfinite(params),",
+			"queryFn: ({ pageParam }) => api.listEvents({ ...params, cursor: pageParam }),",
+			"initialPageParam: undefined as MutationParams<typeof api.listEvents>['cursor'],",
+			"getNextPageParam: (lastPage, _allPages, _lastPageParam) => lastPage.nextCursor ?? undefined,",
+			"initialPageParam: 0 as MutationParams<typeof api.listArchivedEvents>['page'],",
+			"getNextPageParam: (lastPage, allPages, lastPageParam) => (lastPage.results ?? []).length === 0 || allPages.reduce((count, page) => count + (page.results ?? []).length, 0) >= (lastPage.total ?? Infinity) ? undefined : (lastPageParam ?? 0) + 1,",
+		},
+		"stores.ts": {
+			"create<EventsState>()((set, get) => ({",
+			"listEventsPages: Awaited<ReturnType<typeof api.listEvents>>[];",
+			"fetchMorelistEvents: async (params) => {",
+			"listEventsNextPage: nextListEventsPage(result, [result], params[\"cursor\"] ?? undefined) });",
+		},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(files[file], want) {
+				t.Errorf("%s should contain %q:\n%s", file, want, files[file])
+			}
+		}
+	}
+}
+
+func TestRenderCacheEffects(t *testing.T) {
+	hooks := renderSpec(t, "resources.yaml", withTargets("hooks"))["queries.ts"]
+	// onSuccess returns the cache calls of a mutation hook
+	onSuccess := func(id string) string {
+		_, block, _ := strings.Cut(hooks, "mutationFn: api."+id+",\n")
+		block, _, _ = strings.Cut(block, "\n    },\n")
+		return block
+	}
+	for id, wants := range map[string][]string{
+		// An update returning the item replaces its cached data
+		"updateProject": {"queryClient.setQueryData(queryKeys.projects.getProject({ projectId: variables.projectId }), data);"},
+		// A deleted item is dropped along with its sub-resources
+		"deleteProject": {
+			"queryClient.invalidateQueries({ queryKey: queryKeys.projects.listProjects() });",
+			"queryClient.removeQueries({ queryKey: queryKeys.projects.getProject({ projectId: variables.projectId }) });",
+			"queryClient.removeQueries({ queryKey: queryKeys.members.listMembers({ id: variables.projectId }) });",
+		},
+		// An action below an item refreshes the item and its collection
+		"archiveProject": {
+			"queryClient.invalidateQueries({ queryKey: queryKeys.projects.listProjects() });",
+			"queryClient.invalidateQueries({ queryKey: queryKeys.projects.getProject({ projectId: variables.projectId }) });",
+		},
+		// x-radas-invalidates replaces the derived effects
+		"addMember": {
+			"queryClient.invalidateQueries({ queryKey: queryKeys.members.listMembers({ id: variables.id }) });\n      queryClient.invalidateQueries({ queryKey: queryKeys.projects.getProject() });\n      return options?.onSuccess?.",
+		},
+		// Without a related path the queries of the tag are refreshed
+		"reindex": {
+			"queryClient.invalidateQueries({ queryKey: queryKeys.projects.listProjects() });",
+			"queryClient.invalidateQueries({ queryKey: queryKeys.projects.getProject() });",
+		},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(onSuccess(id), want) {
+				t.Errorf("%s should contain %q:\n%s", id, want, onSuccess(id))
+			}
+		}
+	}
+	if !strings.Contains(hooks, "export function useUpdateProjectOptimistic(") || strings.Contains(hooks, "useDeleteProjectOptimistic") {
+		t.Errorf("only mutations updating a cached query should have an optimistic hook")
+	}
+
+	if vue := renderSpec(t, "resources.yaml", withTargets("vue-query"))["queries.ts"]; !strings.Contains(vue, "queryClient.setQueryData(queryKeys.projects.getProject({ projectId: variables.projectId }), data);") {
+		t.Errorf("vue composables should update the cached item:\n%s", vue)
+	}
+}
+
+func TestRenderNamespaces(t *testing.T) {
+	files := renderSpec(t, "petstore.yaml", func(c *Config) { c.Layout = LayoutNamespaces })
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := "client.ts,dto.ts,index.ts,owners/client.ts,owners/dto.ts,owners/index.ts,owners/queries.ts,owners/stores.ts," +
+		"pets/client.ts,pets/dto.ts,pets/index.ts,pets/queries.ts,pets/stores.ts,queryClient.ts,queryKeys.ts"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("rendered files = %s", got)
+	}
+
+	for file, wants := range map[string][]string{
+		"index.ts":     {"export * from './client';", "export * as pets from './pets';"},
+		"queryKeys.ts": {"import type petsApi from './pets/client';", "Partial<Params<typeof petsApi.listPets>>"},
+		// Owner is used by both namespaces and stays shared
+		"dto.ts":           {"export interface Owner {"},
+		"pets/dto.ts":      {"import type { Owner } from '../dto';", "export * from '../dto';", "export type Pet = "},
+		"pets/client.ts":   {"from '../client';", "export type ListPetsError = ", "listPets: async ("},
+		"pets/queries.ts":  {"import { queryClient } from '../queryClient';", "export function useListPets("},
+		"pets/index.ts":    {"export * from './queries';"},
+		"owners/client.ts": {"getOwner: async ("},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(files[file], want) {
+				t.Errorf("%s should contain %q:\n%s", file, want, files[file])
+			}
+		}
+	}
+	if strings.Contains(files["client.ts"], "listPets") || strings.Contains(files["owners/dto.ts"], "interface Pet") {
+		t.Errorf("namespaces should only get their own operations and DTOs")
+	}
+}
+
+func TestRenderMutationOptions(t *testing.T) {
+	// mutation returns the body of the mutation hook generated for id
+	mutation := func(queries, id string) string {
+		_, block, _ := strings.Cut(queries, "export function "+id+"(")
+		block, _, _ = strings.Cut(block, "\n}\n")
+		return block
+	}
+	// The options of the caller come first so the generated cache effects
+	// are kept, and the callbacks of the caller are still called
+	hooks := renderSpec(t, "resources.yaml", withTargets("hooks"))["queries.ts"]
+	vue := renderSpec(t, "resources.yaml", withTargets("vue-query"))["queries.ts"]
+	for name, wants := range map[string][]string{
+		"useUpdateProject": {"...options,\n    mutationFn: api.updateProject,\n    onSuccess: (data, variables, context) => {", "return options?.onSuccess?.(data, variables, context);\n    },\n  });"},
+		"useUpdateProjectOptimistic": {
+			"...options,\n    mutationFn: api.updateProject,\n    onMutate: async (variables) => {\n      await options?.onMutate?.(variables);",
+			"return options?.onError?.(error, variables, context);",
+			"return options?.onSettled?.(data, error, variables, context);\n    },\n  });",
+		},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(mutation(hooks, name), want) {
+				t.Errorf("%s should contain %q:\n%s", name, want, mutation(hooks, name))
+			}
+		}
+	}
+	if got := mutation(vue, "useUpdateProject"); !strings.Contains(got, "...options,\n    mutationFn: api.updateProject,\n    onSuccess:") ||
+		!strings.Contains(got, "return options?.onSuccess?.(data, variables, context);\n    },\n  });") {
+		t.Errorf("vue composables should spread the options before their cache effects:\n%s", got)
+	}
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"regexp"
 
@@ -56,53 +58,6 @@ func last(n int, a interface{}) interface{} {
 	return v.Slice(len-n, len).Interface()
 }
 
-// Helper functions for templates
-func schemaToZodTemplate(s parser.Schema) string {
-	var props []string
-	for k, t := range s.Properties {
-		props = append(props, fmt.Sprintf("%s: %s", k, goTypeToZod(fmt.Sprintf("%v", t))))
-	}
-	return fmt.Sprintf("z.object({ %s }).partial().passthrough()", strings.Join(props, ", "))
-}
-
-// zodType converts a property type to a Zod type
-func zodType(propType interface{}) string {
-	switch val := propType.(type) {
-	case string:
-		switch val {
-		case "string":
-			return "z.string()"
-		case "number":
-			return "z.number()"
-		case "boolean":
-			return "z.boolean()"
-		case "array":
-			return "z.array(z.any())"
-		case "object":
-			return "z.record(z.string(), z.any())"
-		case "null":
-			return "z.null()"
-		default:
-			return "z.any()"
-		}
-	case map[string]interface{}:
-		if val["type"] == "array" {
-			itemType := "z.any()"
-			if items, ok := val["items"].(string); ok {
-				itemType = zodType(items)
-			}
-			return fmt.Sprintf("z.array(%s)", itemType)
-		} else if val["nullable"] == true {
-			baseType := "z.any()"
-			if baseTypeVal, ok := val["type"].(string); ok {
-				baseType = zodType(baseTypeVal)
-			}
-			return fmt.Sprintf("%s.nullable()", baseType)
-		}
-	}
-	return "z.any()"
-}
-
 // pathToTemplate converts an OpenAPI path to a template literal path
 func pathToTemplate(path string) string {
 	// Replace {param} with ${params.param}
@@ -110,47 +65,6 @@ func pathToTemplate(path string) string {
 	return regex.ReplaceAllString(path, "${params.$1}")
 }
 
-func goTypeToTSType(propType interface{}) string {
-	switch val := propType.(type) {
-	case string:
-		switch val {
-		case "string":
-			return "string"
-		case "number":
-			return "number"
-		case "boolean":
-			return "boolean"
-		case "array":
-			return "any[]"
-		case "object":
-			return "Record<string, any>"
-		case "null":
-			// Handle null type from OpenAPI 3.1.0
-			return "null"
-		default:
-			return "any"
-		}
-	case map[string]interface{}:
-		if val["type"] == "array" {
-			itemType := "any"
-			if items, ok := val["items"].(string); ok {
-				itemType = goTypeToTSType(items)
-			}
-			return fmt.Sprintf("%s[]", itemType)
-		} else if val["nullable"] == true {
-			// Handle nullable property
-			baseType := "any"
-			if baseTypeVal, ok := val["type"].(string); ok {
-				baseType = goTypeToTSType(baseTypeVal)
-			}
-			return fmt.Sprintf("%s | null", baseType)
-		}
-		return "Record<string, any>"
-	default:
-		return "any"
-	}
-}
-
 func isRequired(propName string, required interface{}) bool {
 	// Handle case where required might be nil or not a slice
 	reqSlice, ok := required.([]string)
@@ -176,18 +90,86 @@ func hasErrorResponses(responses map[string]parser.Response) bool {
 }
 
 func isErrorStatus(status string) bool {
-	return status != "200" && status != "201" && status != "204"
+	return strings.HasPrefix(status, "4") || strings.HasPrefix(status, "5") || status == "default"
 }
 
-func getResponseSchema(responses map[string]parser.Response) string {
-	for status, resp := range responses {
-		if !isErrorStatus(status) && resp.Schema != "" {
-			return resp.Schema
+// sortedStatuses returns the status codes of the responses in ascending
+// order, with "default" last
+func sortedStatuses(responses map[string]parser.Response) []string {
+	statuses := make([]string, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i] == "default" || statuses[j] == "default" {
+			return statuses[j] == "default" && statuses[i] != "default"
+		}
+		return statuses[i] < statuses[j]
+	})
+	return statuses
+}
+
+// successResponse returns the lowest 2xx response, falling back to the
+// default response when no success status is declared
+func successResponse(responses map[string]parser.Response) (parser.Response, bool) {
+	for _, status := range sortedStatuses(responses) {
+		if strings.HasPrefix(status, "2") {
+			return responses[status], true
 		}
 	}
+	resp, ok := responses["default"]
+	return resp, ok
+}
+
+func getResponseSchema(responses map[string]parser.Response) string {
+	if schema := getSuccessResponseSchema(responses); schema != "" {
+		return schema
+	}
 	return "z.any()"
 }
 
+// errorTypeName returns the name of the error union exported for an operation
+func errorTypeName(id string) string {
+	return capitalize(typeIdentifier(id)) + "Error"
+}
+
+// typeIdentifier strips characters that cannot appear in an identifier
+func typeIdentifier(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// errorType returns the union of ApiError variants declared by the 4xx/5xx
+// and default responses of an operation
+func errorType(responses map[string]parser.Response) string {
+	var variants []string
+	for _, status := range sortedStatuses(responses) {
+		if !isErrorStatus(status) {
+			continue
+		}
+		code := status
+		if _, err := strconv.Atoi(status); err != nil {
+			// "default" and ranges such as "4XX"
+			code = "number"
+		}
+		data := "unknown"
+		if def := responses[status].Def; def != nil {
+			data = dtoType(def)
+		}
+		variants = append(variants, fmt.Sprintf("ApiError<%s, %s>", code, data))
+	}
+	if len(variants) == 0 {
+		variants = append(variants, "ApiError")
+	}
+	variants = append(variants, "ValidationError")
+	return strings.Join(variants, " | ")
+}
+
 func hasParams(op parser.Operation) bool {
 	return len(op.Parameters) > 0 || op.RequestBody != nil
 }
@@ -234,20 +216,108 @@ func paramTypeTemplate(op parser.Operation) string {
 	return result.String()
 }
 
+// returnTypeTemplate returns the TypeScript type of the successful response
 func returnTypeTemplate(responses map[string]parser.Response) string {
-	for _, resp := range responses {
-		if resp.Schema != "" && resp.Schema != "z.any()" {
-			schemaName := strings.TrimPrefix(strings.TrimSuffix(resp.Schema, "))"), "z.")
-			if strings.HasPrefix(schemaName, "object") {
-				return "Record<string, any>"
-			} else if strings.HasPrefix(schemaName, "array") {
-				return "any[]"
-			} else {
-				return schemaName
-			}
+	resp, ok := successResponse(responses)
+	if !ok {
+		return "any"
+	}
+	switch responseBodyKind(resp) {
+	case "":
+		return "void"
+	case "json":
+		if resp.Def != nil {
+			return dtoType(resp.Def)
+		}
+		return "any"
+	case "text":
+		return "string"
+	default:
+		return "Blob"
+	}
+}
+
+// bodyKind classifies a media type by how a generated client encodes or
+// decodes it: json, multipart, form, text or binary
+func bodyKind(mediaType string) string {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	switch {
+	case mediaType == "":
+		return ""
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return "json"
+	case mediaType == "multipart/form-data":
+		return "multipart"
+	case mediaType == "application/x-www-form-urlencoded":
+		return "form"
+	case strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "xml"):
+		return "text"
+	default:
+		return "binary"
+	}
+}
+
+// responseBodyKind is the bodyKind of a response, JSON when only its schema
+// is known
+func responseBodyKind(resp parser.Response) string {
+	if resp.ContentType == "" && resp.Def != nil {
+		return "json"
+	}
+	return bodyKind(resp.ContentType)
+}
+
+// requestBodyType returns the TypeScript type of a request body; multipart
+// and form bodies keep their object type and are encoded by the client
+func requestBodyType(rb *parser.RequestBody) string {
+	switch bodyKind(rb.ContentType) {
+	case "text":
+		return "string"
+	case "binary":
+		return "Blob"
+	default:
+		return dtoType(rb.Def)
+	}
+}
+
+// responseKind returns how the client reads the successful response body:
+// json, text or blob
+func responseKind(responses map[string]parser.Response) string {
+	resp, _ := successResponse(responses)
+	switch responseBodyKind(resp) {
+	case "", "json":
+		return "json"
+	case "text":
+		return "text"
+	default:
+		return "blob"
+	}
+}
+
+// successContentType returns the media type of the successful response
+func successContentType(responses map[string]parser.Response) string {
+	resp, _ := successResponse(responses)
+	return resp.ContentType
+}
+
+// hasEncodedBodies reports whether any operation sends a body that is not
+// JSON, so the client needs the body encoders
+func hasEncodedBodies(ops []parser.Operation) bool {
+	for _, op := range ops {
+		if op.RequestBody != nil && !isJSONOperation(op) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONOperation reports whether an operation sends and receives JSON only
+func isJSONOperation(op parser.Operation) bool {
+	if op.RequestBody != nil {
+		if kind := bodyKind(op.RequestBody.ContentType); kind != "" && kind != "json" {
+			return false
 		}
 	}
-	return "any"
+	return responseKind(op.Responses) == "json"
 }
 
 func shouldInvalidateCache(op parser.Operation) bool {
@@ -324,9 +394,6 @@ func extractTSType(schema string) string {
 		
 		return baseType
 	}
-	
-	// Handle direct reference to a schema name
-	return schema
 }
 
 // extractDTOType extracts a DTO type name from a schema reference
@@ -403,84 +470,6 @@ func extractDTOType(schema interface{}) string {
 	return schemaStr
 }
 
-// returnTypePromise returns the TypeScript type for a Promise returned by an API function
-func returnTypePromise(responses map[string]parser.Response) string {
-	for status, resp := range responses {
-		if status == "200" || status == "201" || status == "204" {
-			if resp.Schema != "" && resp.Schema != "z.any()" {
-				schemaName := extractDTOType(resp.Schema)
-				if schemaName != "" {
-					return schemaName
-				}
-				
-				// If not a named schema, determine basic type
-				if strings.HasPrefix(resp.Schema, "z.array") {
-					return "any[]"
-				} else if resp.Schema == "z.string()" {
-					return "string"
-				} else if resp.Schema == "z.number()" {
-					return "number"
-				} else if resp.Schema == "z.boolean()" {
-					return "boolean"
-				}
-			}
-		}
-	}
-
-	return "any"
-}
-
-// shouldInvalidateQueries determines if queries should be invalidated for this operation
-func shouldInvalidateQueries(op parser.Operation) bool {
-	method := strings.ToUpper(op.Method)
-	return method == "POST" || method == "PUT" || method == "PATCH" || method == "DELETE"
-}
-
-// hasRelatedGetOperation checks if there are GET operations related to this operation
-func hasRelatedGetOperation(op parser.Operation, operations []parser.Operation) bool {
-	// No entity, no related operations
-	if op.Entity == "" {
-		return false
-	}
-	
-	// Look for GET operations with the same entity
-	for _, otherOp := range operations {
-		if strings.ToUpper(otherOp.Method) == "GET" && otherOp.Entity == op.Entity {
-			return true
-		}
-	}
-	
-	return false
-}
-
-// getRelatedListOperation gets the related GET operation that returns a list
-func getRelatedListOperation(op parser.Operation, operations []parser.Operation) string {
-	// Get all GET operations with the same entity
-	for _, otherOp := range operations {
-		if strings.ToUpper(otherOp.Method) == "GET" && 
-		   otherOp.Entity == op.Entity && 
-		   !strings.Contains(otherOp.Path, "/{id}") { // Heuristic for list operation
-			return otherOp.ID
-		}
-	}
-	
-	return ""
-}
-
-// getRelatedGetOperation gets the related GET operation that returns a single item
-func getRelatedGetOperation(op parser.Operation, operations []parser.Operation) string {
-	// Get all GET operations with the same entity
-	for _, otherOp := range operations {
-		if strings.ToUpper(otherOp.Method) == "GET" && 
-		   otherOp.Entity == op.Entity && 
-		   strings.Contains(otherOp.Path, "/{id}") { // Heuristic for get-by-id operation
-			return otherOp.ID
-		}
-	}
-	
-	return ""
-}
-
 // pathWithParams formats a URL path replacing path params with JavaScript template syntax
 func pathWithParams(path string) string {
 	// Replace {param} with ${params.param}
@@ -495,16 +484,11 @@ func pathWithParams(path string) string {
 	return "'" + path + "'"
 }
 
-// getSuccessResponseSchema returns the schema for the successful response
+// getSuccessResponseSchema returns the Zod schema of the successful response
 func getSuccessResponseSchema(responses map[string]parser.Response) string {
-	for status, resp := range responses {
-		if status == "200" || status == "201" || status == "204" {
-			if resp.Schema != "" {
-				return resp.Schema
-			}
-		}
+	if resp, ok := successResponse(responses); ok && resp.Def != nil {
+		return zodType(resp.Def)
 	}
-	
 	return ""
 }
 
@@ -537,3 +521,122 @@ func hasHeaderParams(op parser.Operation) bool {
 	}
 	return false
 }
+
+// securityLiteral renders the security requirements of an operation as a
+// JavaScript array, e.g. [{ bearerAuth: [] }, { oauth: ["read"] }]
+func securityLiteral(requirements []parser.SecurityRequirement) string {
+	items := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		entries := make([]string, 0, len(names))
+		for _, name := range names {
+			entries = append(entries, fmt.Sprintf("%s: %s", propertyKey(name), jsLiteral(requirement[name])))
+		}
+		if len(entries) == 0 {
+			items = append(items, "{}")
+			continue
+		}
+		items = append(items, "{ "+strings.Join(entries, ", ")+" }")
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// credentialType returns the TypeScript type of the credential a security
+// scheme is satisfied with
+func credentialType(scheme parser.SecurityScheme) string {
+	if scheme.Type == "http" && scheme.Scheme == "basic" {
+		return "BasicCredentials"
+	}
+	return "string"
+}
+
+// hasPagination reports whether any of the operations is paginated
+func hasPagination(ops []parser.Operation) bool {
+	for _, op := range ops {
+		if op.Pagination != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// initialPageParam returns the page parameter of the first page
+func initialPageParam(p *parser.Pagination) string {
+	switch p.Style {
+	case parser.PaginationPage:
+		return strconv.Itoa(p.FirstPage)
+	case parser.PaginationOffset:
+		return "0"
+	default:
+		return "undefined"
+	}
+}
+
+// fieldAccess renders a dot separated response field read from expr,
+// optionally chaining past the first property
+func fieldAccess(expr, field string) string {
+	return expr + "." + strings.ReplaceAll(field, ".", "?.")
+}
+
+// nextPageParam renders the function computing the page parameter of the
+// page after lastPage, undefined once every page is loaded. With pageType
+// and paramType set its parameters are annotated.
+func nextPageParam(p *parser.Pagination, pageType, paramType string) string {
+	items := func(page string) string {
+		if p.ItemsField == "" {
+			return page
+		}
+		return "(" + fieldAccess(page, p.ItemsField) + " ?? [])"
+	}
+
+	usesAll := p.TotalField != "" && p.Style != parser.PaginationCursor
+	usesParam := p.Style != parser.PaginationCursor
+	args := []string{"lastPage", "allPages", "lastPageParam"}
+	if !usesAll {
+		args[1] = "_allPages"
+	}
+	if !usesParam {
+		args[2] = "_lastPageParam"
+	}
+	if pageType != "" {
+		args[0] += ": " + pageType
+		args[1] += ": " + pageType + "[]"
+		args[2] += ": " + paramType
+	}
+
+	var next string
+	switch p.Style {
+	case parser.PaginationCursor:
+		next = fieldAccess("lastPage", p.NextField) + " ?? undefined"
+	default:
+		done := items("lastPage") + ".length === 0"
+		if usesAll {
+			loaded := "allPages.reduce((count, page) => count + " + items("page") + ".length, 0)"
+			done += " || " + loaded + " >= (" + fieldAccess("lastPage", p.TotalField) + " ?? Infinity)"
+		}
+		step := "1"
+		if p.Style == parser.PaginationOffset {
+			step = items("lastPage") + ".length"
+		}
+		next = fmt.Sprintf("%s ? undefined : (lastPageParam ?? %s) + %s", done, initialPageParam(p), step)
+	}
+	return fmt.Sprintf("(%s) => %s", strings.Join(args, ", "), next)
+}
+
+// keyNamespace names the group of query keys of a namespace, like the
+// namespaced API objects of the client
+func keyNamespace(namespace string) string {
+	if namespace == "" {
+		namespace = "api"
+	}
+	return camelCase(strings.ReplaceAll(strings.ReplaceAll(namespace, " - ", ""), " ", ""))
+}
+
+// queryKey returns the expression of the key factory of a GET operation
+func queryKey(op parser.Operation) string {
+	return "queryKeys." + keyNamespace(op.Namespace) + "." + op.ID
+}
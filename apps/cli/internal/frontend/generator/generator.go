@@ -4,6 +4,7 @@ package generator
 import (
 	"radas/internal/frontend/generator/api"
 	"radas/internal/frontend/generator/styles"
+	"radas/internal/frontend/parser"
 )
 
 
@@ -12,7 +13,6 @@ func GenerateAPI(inputSpec, outputDir, baseURL string, verbose bool, skipValidat
 		InputSpec:      inputSpec,
 		OutputDir:      outputDir,
 		BaseURL:        baseURL,
-		GenerateAll:    true,
 		Verbose:        verbose,
 		SkipValidation: skipValidation,
 		ErrorsOnly:     errorsOnly,
@@ -20,7 +20,51 @@ func GenerateAPI(inputSpec, outputDir, baseURL string, verbose bool, skipValidat
 	generator := api.New(config)
 	return generator.Generate()
 }
-	 
+
+// APIOptions configures generation for a single API contract
+type APIOptions struct {
+	InputSpec      string
+	OutputDir      string
+	BaseURL        string
+	Contract       string
+	Targets        []string // registry targets, api.DefaultTargets when empty
+	TemplatesDir   string   // user targets layered over the built-in ones
+	Validate       bool     // runtime Zod validation in the fetch and axios clients
+	Verbose        bool
+	SkipValidation bool
+	ErrorsOnly     bool
+	Pagination     parser.PaginationConventions // names that mark list operations as paginated
+	Layout         string                       // api.LayoutSingle (default) or api.LayoutNamespaces
+}
+
+func (o APIOptions) config() *api.Config {
+	return &api.Config{
+		InputSpec:      o.InputSpec,
+		OutputDir:      o.OutputDir,
+		BaseURL:        o.BaseURL,
+		Contract:       o.Contract,
+		Targets:        o.Targets,
+		TemplatesDir:   o.TemplatesDir,
+		Validate:       o.Validate,
+		Verbose:        o.Verbose,
+		SkipValidation: o.SkipValidation,
+		ErrorsOnly:     o.ErrorsOnly,
+		Pagination:     o.Pagination,
+		Layout:         o.Layout,
+	}
+}
+
+// GenerateAPIContract generates the client for a single API contract
+func GenerateAPIContract(opts APIOptions) error {
+	return api.New(opts.config()).Generate()
+}
+
+// CheckAPI renders the API client in memory and returns the files in the
+// output directory that differ from it, without writing anything
+func CheckAPI(opts APIOptions) ([]api.FileDrift, error) {
+	return api.New(opts.config()).Check()
+}
+
 func GenerateStyles(sourceDir, outputDir string, types []string) error {
 	generator := styles.NewStylesGenerator(sourceDir, outputDir, types)
 	return generator.Generate()
@@ -0,0 +1,378 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"radas/constants"
+	"radas/internal/frontend/generator/api"
+	"radas/internal/frontend/generator/styles"
+)
+
+// ManifestFileName is written into the __generated__ directory
+const ManifestFileName = ".radas-manifest.json"
+
+const manifestVersion = 1
+
+// Manifest records what every contract and token source was generated from
+// and what was written, so unchanged inputs can be skipped and generated
+// files edited by hand can be told apart from stale ones
+type Manifest struct {
+	Version   int                      `json:"version"`
+	Generator string                   `json:"generator"` // radas version that wrote the files
+	Entries   map[string]ManifestEntry `json:"entries"`   // by contract or token source
+	Files     map[string]string        `json:"files"`     // sha256 of every generated file, relative to the manifest
+
+	path string
+}
+
+// ManifestEntry is one generation input and the files it produced
+type ManifestEntry struct {
+	Inputs string   `json:"inputs"` // sha256 over the sources, options and generator version
+	Files  []string `json:"files"`
+}
+
+// ReadManifest loads a manifest; a missing file, or one written in an older
+// format, yields an empty manifest
+func ReadManifest(path string) (*Manifest, error) {
+	m := &Manifest{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+		}
+	}
+	if m.Version != manifestVersion || m.Entries == nil || m.Files == nil {
+		m.Entries = make(map[string]ManifestEntry)
+		m.Files = make(map[string]string)
+	}
+	return m, nil
+}
+
+// Write saves the manifest, leaving it untouched when nothing changed
+func (m *Manifest) Write() error {
+	m.Version = manifestVersion
+	m.Generator = constants.Version
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if current, err := os.ReadFile(m.path); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(m.path), err)
+	}
+	if err := os.WriteFile(m.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// GenerateAPI generates the client of a contract unless the manifest shows
+// it was already generated from the same spec and options. It reports
+// whether anything was generated.
+func (m *Manifest) GenerateAPI(key string, opts APIOptions, force bool) (bool, error) {
+	inputs, err := apiInputs(opts)
+	if err != nil {
+		return false, err
+	}
+	if !force && m.unchanged(key, inputs) {
+		return false, nil
+	}
+
+	start := time.Now()
+	gen := api.New(opts.config())
+	files, timings, err := gen.Render()
+	if err != nil {
+		return false, err
+	}
+	writeStart := time.Now()
+	if err := m.writeFiles(key, inputs, opts.OutputDir, files); err != nil {
+		return false, err
+	}
+	timings = append(timings, api.Timing{Stage: "write", Duration: time.Since(writeStart)})
+
+	if opts.Verbose {
+		timings.Print(time.Since(start))
+		fmt.Printf("✅ Code generation completed in: %s\n", opts.OutputDir)
+	}
+	return true, nil
+}
+
+// GenerateStyles generates style variables from design tokens unless the
+// manifest shows they were already generated from the same tokens. It reports
+// whether anything was generated.
+func (m *Manifest) GenerateStyles(key, sourceDir, outputDir string, types []string, force bool) (bool, error) {
+	inputs, err := styleInputs(sourceDir, types)
+	if err != nil {
+		return false, err
+	}
+	if !force && m.unchanged(key, inputs) {
+		return false, nil
+	}
+
+	// The styles generator writes every file, so it renders into a scratch
+	// directory and only the files whose content changed are copied over
+	scratch, err := os.MkdirTemp("", "radas-styles-")
+	if err != nil {
+		return false, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+	gen := styles.NewStylesGenerator(sourceDir, scratch, types)
+	gen.Quiet = true
+	if err := gen.Generate(); err != nil {
+		return false, err
+	}
+	files, err := readTree(scratch)
+	if err != nil {
+		return false, err
+	}
+	return true, m.writeFiles(key, inputs, outputDir, files)
+}
+
+// unchanged reports whether the entry was generated from the same inputs and
+// all of its files are still on disk as they were written
+func (m *Manifest) unchanged(key, inputs string) bool {
+	entry, ok := m.Entries[key]
+	if !ok || entry.Inputs != inputs {
+		return false
+	}
+	for _, name := range entry.Files {
+		content, err := os.ReadFile(m.abs(name))
+		if err != nil || hashBytes(content) != m.Files[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeFiles writes the files of an entry into dir. Files whose content did
+// not change are left alone so their mtime is kept, files edited by hand are
+// reported before they are overwritten, and files the entry no longer
+// generates are removed unless they were edited.
+func (m *Manifest) writeFiles(key, inputs, dir string, files map[string]string) error {
+	generated := make(map[string]bool, len(files))
+	var names []string
+	for _, name := range sortedKeys(files) {
+		filePath := filepath.Join(dir, name)
+		rel := m.rel(filePath)
+		generated[rel] = true
+		names = append(names, rel)
+
+		content := []byte(files[name])
+		current, err := os.ReadFile(filePath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		if err == nil {
+			if bytes.Equal(current, content) {
+				m.Files[rel] = hashBytes(content)
+				continue
+			}
+			if recorded, ok := m.Files[rel]; ok && hashBytes(current) != recorded {
+				fmt.Printf("⚠️ %s was edited by hand, overwriting it with the generated code\n", filePath)
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(filePath), err)
+		}
+		if err := os.WriteFile(filePath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filePath, err)
+		}
+		m.Files[rel] = hashBytes(content)
+	}
+
+	for _, name := range m.Entries[key].Files {
+		if generated[name] {
+			continue
+		}
+		filePath := m.abs(name)
+		current, err := os.ReadFile(filePath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return fmt.Errorf("failed to read %s: %w", filePath, err)
+		case hashBytes(current) != m.Files[name]:
+			fmt.Printf("⚠️ %s is no longer generated but was edited by hand, keeping it\n", filePath)
+		default:
+			if err := os.Remove(filePath); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", filePath, err)
+			}
+		}
+		delete(m.Files, name)
+	}
+
+	m.Entries[key] = ManifestEntry{Inputs: inputs, Files: names}
+	return nil
+}
+
+// rel names a file relative to the manifest, with forward slashes
+func (m *Manifest) rel(filePath string) string {
+	if rel, err := filepath.Rel(filepath.Dir(m.path), filePath); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(filePath)
+}
+
+func (m *Manifest) abs(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(filepath.Dir(m.path), filepath.FromSlash(name))
+}
+
+// apiInputs hashes everything the client of a contract is generated from
+func apiInputs(opts APIOptions) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "radas %s\n", constants.Version)
+
+	options := opts
+	options.InputSpec = ""
+	options.Verbose = false
+	if err := json.NewEncoder(h).Encode(options); err != nil {
+		return "", err
+	}
+	if err := hashSpec(h, opts.InputSpec); err != nil {
+		return "", err
+	}
+	if opts.TemplatesDir != "" {
+		if err := hashTree(h, opts.TemplatesDir); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// styleInputs hashes the design tokens and the style types generated from them
+func styleInputs(sourceDir string, types []string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "radas %s\n%s\n", constants.Version, strings.Join(types, ","))
+	if err := hashTree(h, sourceDir); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// externalRefPattern matches $ref values pointing into other files
+var externalRefPattern = regexp.MustCompile(`\$ref["']?\s*:\s*["']?([^"'#\s]+)`)
+
+// SpecFiles returns a spec followed by the local files it refers to through
+// $ref, directly or through other referenced files
+func SpecFiles(specPath string) ([]string, error) {
+	return ReadSpecFiles(specPath, os.ReadFile)
+}
+
+// ReadSpecFiles is SpecFiles reading every file with read, such as from
+// another git revision
+func ReadSpecFiles(specPath string, read func(filePath string) ([]byte, error)) ([]string, error) {
+	var files []string
+	err := walkSpec(specPath, read, make(map[string]bool), func(filePath string, _ []byte) {
+		files = append(files, filePath)
+	})
+	return files, err
+}
+
+// hashSpec hashes a spec together with the local files it refers to
+func hashSpec(h hash.Hash, specPath string) error {
+	return walkSpec(specPath, os.ReadFile, make(map[string]bool), func(filePath string, content []byte) {
+		fmt.Fprintf(h, "%s %d\n", filepath.Base(filePath), len(content))
+		h.Write(content)
+	})
+}
+
+// walkSpec calls visit with a spec and then with every local file it refers
+// to, once each. References that cannot be read are skipped, the parser
+// reports them.
+func walkSpec(specPath string, read func(filePath string) ([]byte, error), seen map[string]bool, visit func(filePath string, content []byte)) error {
+	seen[specPath] = true
+	content, err := read(specPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", specPath, err)
+	}
+	visit(specPath, content)
+
+	for _, match := range externalRefPattern.FindAllSubmatch(content, -1) {
+		ref := string(match[1])
+		if strings.Contains(ref, "://") {
+			continue
+		}
+		refPath := filepath.Join(filepath.Dir(specPath), filepath.FromSlash(ref))
+		if seen[refPath] {
+			continue
+		}
+		// Broken references are reported by the parser
+		walkSpec(refPath, read, seen, visit)
+	}
+	return nil
+}
+
+// hashTree hashes the names and content of every file under dir; a missing
+// dir hashes as empty
+func hashTree(h hash.Hash, dir string) error {
+	files, err := readTree(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(files) {
+		fmt.Fprintf(h, "%s %d\n", name, len(files[name]))
+		h.Write([]byte(files[name]))
+	}
+	return nil
+}
+
+// readTree reads every file under dir, keyed by slash separated relative path
+func readTree(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	return files, err
+}
+
+func hashBytes(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedKeys(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"radas/internal/frontend/generator/api"
+)
+
+func TestManifestGenerateAPI(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "petstore.yaml")
+	content, err := os.ReadFile(filepath.Join("api", "testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spec, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifestPath := filepath.Join(dir, "__generated__", ManifestFileName)
+	out := filepath.Join(dir, "__generated__", "api")
+	opts := APIOptions{InputSpec: spec, OutputDir: out}
+
+	generate := func(opts APIOptions) bool {
+		t.Helper()
+		m, err := ReadManifest(manifestPath)
+		if err != nil {
+			t.Fatalf("ReadManifest() error = %v", err)
+		}
+		generated, err := m.GenerateAPI("api", opts, false)
+		if err != nil {
+			t.Fatalf("GenerateAPI() error = %v", err)
+		}
+		if err := m.Write(); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		return generated
+	}
+
+	if !generate(opts) {
+		t.Fatal("first run should generate")
+	}
+	if generate(opts) {
+		t.Error("unchanged inputs should be skipped")
+	}
+
+	// A changed option regenerates, but files with the same content keep their mtime
+	dto := filepath.Join(out, "dto.ts")
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(dto, old, old); err != nil {
+		t.Fatal(err)
+	}
+	opts.BaseURL = "https://api.example.com"
+	if !generate(opts) {
+		t.Fatal("changed options should regenerate")
+	}
+	if info, err := os.Stat(dto); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("unchanged dto.ts was rewritten")
+	}
+
+	// Hand edits are regenerated, and files no longer generated are removed
+	if err := os.WriteFile(dto, []byte("// edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !generate(opts) {
+		t.Fatal("hand edited output should regenerate")
+	}
+	if got, _ := os.ReadFile(dto); string(got) == "// edited\n" {
+		t.Error("hand edit was not overwritten")
+	}
+	opts.Layout = api.LayoutNamespaces
+	generate(opts)
+	if _, err := os.Stat(filepath.Join(out, "queries.ts")); !os.IsNotExist(err) {
+		t.Errorf("queries.ts from the single layout should be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "pets", "queries.ts")); err != nil {
+		t.Errorf("pets/queries.ts should be generated: %v", err)
+	}
+}
+
+func TestManifestGenerateAPIVerbose(t *testing.T) {
+	dir := t.TempDir()
+	m, err := ReadManifest(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	out := filepath.Join(dir, "api")
+	opts := APIOptions{InputSpec: filepath.Join("api", "testdata", "petstore.yaml"), OutputDir: out, Verbose: true}
+
+	output := captureStdout(t, func() {
+		if _, err := m.GenerateAPI("api", opts, false); err != nil {
+			t.Fatalf("GenerateAPI() error = %v", err)
+		}
+	})
+	for _, want := range []string{"[GEN] Timing breakdown:", "  parse ", "  render queries.ts ", "  write ", "  total ", "✅ Code generation completed in: " + out} {
+		if !strings.Contains(output, want) {
+			t.Errorf("verbose output should contain %q:\n%s", want, output)
+		}
+	}
+}
+
+// captureStdout returns what fn printed
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-output
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -13,6 +14,7 @@ type StylesGenerator struct {
 	SourceDir  string
 	OutputDir  string
 	TypesToGen []string
+	Quiet      bool // skips the summary printed after generating
 }
 
 // TokenData represents the structure of a design token file
@@ -80,7 +82,9 @@ func (g *StylesGenerator) Generate() error {
 		}
 	}
 
-	fmt.Printf("✅ Successfully generated styles in %s\n", g.OutputDir)
+	if !g.Quiet {
+		fmt.Printf("✅ Successfully generated styles in %s\n", g.OutputDir)
+	}
 	return nil
 }
 
@@ -121,6 +125,26 @@ func (g *StylesGenerator) processTokensDirectory(dir string) (map[string]TokenDa
 	return tokens, nil
 }
 
+// sortedCategories returns the token group names in alphabetical order
+func sortedCategories(tokens map[string]TokenData) []string {
+	names := make([]string, 0, len(tokens))
+	for name := range tokens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedTokenKeys returns the flattened token names in alphabetical order
+func sortedTokenKeys(tokens map[string]interface{}) []string {
+	keys := make([]string, 0, len(tokens))
+	for key := range tokens {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // flattenTokens flattens nested token structures into a flat map with dot notation
 func flattenTokens(prefix string, data interface{}, result map[string]interface{}) {
 	switch v := data.(type) {
@@ -169,7 +193,8 @@ func (g *StylesGenerator) generateCSS(foundationTokens, componentTokens map[stri
 	sb.WriteString(":root {\n")
 	
 	// Process foundation tokens first
-	for categoryName, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("  /* %s */\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -177,14 +202,16 @@ func (g *StylesGenerator) generateCSS(foundationTokens, componentTokens map[stri
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("  --%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
 	}
 	
 	// Process component tokens
-	for categoryName, tokens := range componentTokens {
+	for _, categoryName := range sortedCategories(componentTokens) {
+		tokens := componentTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("  /* %s Component */\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -192,7 +219,8 @@ func (g *StylesGenerator) generateCSS(foundationTokens, componentTokens map[stri
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("  --%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
@@ -204,14 +232,16 @@ func (g *StylesGenerator) generateCSS(foundationTokens, componentTokens map[stri
 	sb.WriteString(".dark {\n")
 	
 	// Check for dark mode values in foundation tokens
-	for _, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		if colorDark, ok := tokens["color-dark"]; ok {
 			sb.WriteString("  /* Dark Theme Colors */\n")
 			flatTokens := make(map[string]interface{})
 			flattenTokens("color", colorDark, flatTokens)
 			
 			// Write the flattened tokens
-			for key, value := range flatTokens {
+			for _, key := range sortedTokenKeys(flatTokens) {
+				value := flatTokens[key]
 				sb.WriteString(fmt.Sprintf("  --%s: %v;\n", key, value))
 			}
 			sb.WriteString("\n")
@@ -241,7 +271,8 @@ func (g *StylesGenerator) generateSCSS(foundationTokens, componentTokens map[str
 	sb.WriteString("// Light Theme\n")
 	
 	// Process foundation tokens first
-	for categoryName, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("// %s\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -249,14 +280,16 @@ func (g *StylesGenerator) generateSCSS(foundationTokens, componentTokens map[str
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("$%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
 	}
 	
 	// Process component tokens
-	for categoryName, tokens := range componentTokens {
+	for _, categoryName := range sortedCategories(componentTokens) {
+		tokens := componentTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("// %s Component\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -264,7 +297,8 @@ func (g *StylesGenerator) generateSCSS(foundationTokens, componentTokens map[str
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("$%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
@@ -274,14 +308,16 @@ func (g *StylesGenerator) generateSCSS(foundationTokens, componentTokens map[str
 	sb.WriteString("// Dark Theme\n")
 	
 	// Check for dark mode values in foundation tokens
-	for _, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		if colorDark, ok := tokens["color-dark"]; ok {
 			sb.WriteString("// Dark Theme Colors\n")
 			flatTokens := make(map[string]interface{})
 			flattenTokens("color-dark", colorDark, flatTokens)
 			
 			// Write the flattened tokens
-			for key, value := range flatTokens {
+			for _, key := range sortedTokenKeys(flatTokens) {
+				value := flatTokens[key]
 				sb.WriteString(fmt.Sprintf("$%s: %v;\n", key, value))
 			}
 			sb.WriteString("\n")
@@ -307,7 +343,8 @@ func (g *StylesGenerator) generateLESS(foundationTokens, componentTokens map[str
 	sb.WriteString("// Light Theme\n")
 	
 	// Process foundation tokens first
-	for categoryName, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("// %s\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -315,14 +352,16 @@ func (g *StylesGenerator) generateLESS(foundationTokens, componentTokens map[str
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("@%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
 	}
 	
 	// Process component tokens
-	for categoryName, tokens := range componentTokens {
+	for _, categoryName := range sortedCategories(componentTokens) {
+		tokens := componentTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("// %s Component\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -330,7 +369,8 @@ func (g *StylesGenerator) generateLESS(foundationTokens, componentTokens map[str
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("@%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
@@ -340,14 +380,16 @@ func (g *StylesGenerator) generateLESS(foundationTokens, componentTokens map[str
 	sb.WriteString("// Dark Theme\n")
 	
 	// Check for dark mode values in foundation tokens
-	for _, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		if colorDark, ok := tokens["color-dark"]; ok {
 			sb.WriteString("// Dark Theme Colors\n")
 			flatTokens := make(map[string]interface{})
 			flattenTokens("color-dark", colorDark, flatTokens)
 			
 			// Write the flattened tokens
-			for key, value := range flatTokens {
+			for _, key := range sortedTokenKeys(flatTokens) {
+				value := flatTokens[key]
 				sb.WriteString(fmt.Sprintf("@%s: %v;\n", key, value))
 			}
 			sb.WriteString("\n")
@@ -373,7 +415,8 @@ func (g *StylesGenerator) generateCSSModules(foundationTokens, componentTokens m
 	sb.WriteString(":root {\n")
 	
 	// Process foundation tokens first
-	for categoryName, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("  /* %s */\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -381,14 +424,16 @@ func (g *StylesGenerator) generateCSSModules(foundationTokens, componentTokens m
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("  --%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
 	}
 	
 	// Process component tokens
-	for categoryName, tokens := range componentTokens {
+	for _, categoryName := range sortedCategories(componentTokens) {
+		tokens := componentTokens[categoryName]
 		sb.WriteString(fmt.Sprintf("  /* %s Component */\n", strings.Title(categoryName)))
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
@@ -396,7 +441,8 @@ func (g *StylesGenerator) generateCSSModules(foundationTokens, componentTokens m
 		}
 		
 		// Write the flattened tokens
-		for key, value := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
+			value := flatTokens[key]
 			sb.WriteString(fmt.Sprintf("  --%s: %v;\n", key, value))
 		}
 		sb.WriteString("\n")
@@ -408,14 +454,16 @@ func (g *StylesGenerator) generateCSSModules(foundationTokens, componentTokens m
 	sb.WriteString(".dark {\n")
 	
 	// Check for dark mode values in foundation tokens
-	for _, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		if colorDark, ok := tokens["color-dark"]; ok {
 			sb.WriteString("  /* Dark Theme Colors */\n")
 			flatTokens := make(map[string]interface{})
 			flattenTokens("color", colorDark, flatTokens)
 			
 			// Write the flattened tokens
-			for key, value := range flatTokens {
+			for _, key := range sortedTokenKeys(flatTokens) {
+				value := flatTokens[key]
 				sb.WriteString(fmt.Sprintf("  --%s: %v;\n", key, value))
 			}
 			sb.WriteString("\n")
@@ -428,14 +476,15 @@ func (g *StylesGenerator) generateCSSModules(foundationTokens, componentTokens m
 	sb.WriteString("/* Exports for CSS Modules */\n")
 	
 	// Process foundation tokens
-	for _, tokens := range foundationTokens {
+	for _, categoryName := range sortedCategories(foundationTokens) {
+		tokens := foundationTokens[categoryName]
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
 			flattenTokens(key, value, flatTokens)
 		}
 		
 		// Write the flattened tokens as exports
-		for key := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
 			// Create camelCase version of key for JS
 			parts := strings.Split(key, "-")
 			for i := 1; i < len(parts); i++ {
@@ -450,14 +499,15 @@ func (g *StylesGenerator) generateCSSModules(foundationTokens, componentTokens m
 	}
 	
 	// Process component tokens
-	for _, tokens := range componentTokens {
+	for _, categoryName := range sortedCategories(componentTokens) {
+		tokens := componentTokens[categoryName]
 		flatTokens := make(map[string]interface{})
 		for key, value := range tokens {
 			flattenTokens(key, value, flatTokens)
 		}
 		
 		// Write the flattened tokens as exports
-		for key := range flatTokens {
+		for _, key := range sortedTokenKeys(flatTokens) {
 			// Create camelCase version of key for JS
 			parts := strings.Split(key, "-")
 			for i := 1; i < len(parts); i++ {
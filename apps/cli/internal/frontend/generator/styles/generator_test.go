@@ -0,0 +1,45 @@
+package styles
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var goldenFiles = []string{"variables.css", "variables.scss", "variables.less", "variables.module.css"}
+
+// TestGenerateGolden guards the generated styles against regressions and
+// against ordering that depends on Go map iteration
+func TestGenerateGolden(t *testing.T) {
+	for run := 0; run < 3; run++ {
+		outDir := t.TempDir()
+		gen := NewStylesGenerator(filepath.Join("testdata", "tokens"), outDir, nil)
+		if err := gen.Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		for _, name := range goldenFiles {
+			got, err := os.ReadFile(filepath.Join(outDir, name))
+			if err != nil {
+				t.Fatalf("failed to read generated %s: %v", name, err)
+			}
+			goldenPath := filepath.Join("testdata", "golden", name+".golden")
+			if *update && run == 0 {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatalf("failed to update %s: %v", goldenPath, err)
+				}
+				continue
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden %s: %v", goldenPath, err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("run %d: %s does not match %s (run with -update to refresh)", run, name, goldenPath)
+			}
+		}
+	}
+}
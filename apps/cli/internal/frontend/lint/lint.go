@@ -0,0 +1,166 @@
+// Package lint checks OpenAPI specs for problems that make gen-api produce
+// poor code even though the spec itself is valid
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Severity is how serious an issue is; Off disables a rule
+type Severity int
+
+const (
+	Off Severity = iota
+	Info
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return "off"
+}
+
+// MarshalJSON writes the severity by name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// ParseSeverity parses a severity as written in radas.yml
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "off", "false", "none":
+		return Off, nil
+	case "info", "hint":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error", "true":
+		return Error, nil
+	}
+	return Off, fmt.Errorf("unknown severity %q (use off, info, warning or error)", s)
+}
+
+// Issue is a problem found in a spec
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Location string   `json:"location"` // operation (METHOD /path) or component pointer
+	Message  string   `json:"message"`
+}
+
+// Rule is a single check with the severity it reports at by default
+type Rule struct {
+	ID          string
+	Description string
+	Severity    Severity
+	check       func(doc *openapi3.T, report func(location, format string, args ...interface{}))
+}
+
+// Rules returns the built-in rules in a stable order
+func Rules() []Rule {
+	return rules
+}
+
+// Config overrides the severity of rules by ID
+type Config map[string]Severity
+
+// ParseConfig reads rule severities as written in radas.yml and rejects
+// rules that do not exist
+func ParseConfig(raw map[string]string) (Config, error) {
+	cfg := make(Config, len(raw))
+	for _, id := range sortedKeys(raw) {
+		if !knownRule(id) {
+			ids := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				ids = append(ids, rule.ID)
+			}
+			return nil, fmt.Errorf("unknown lint rule %q (available: %s)", id, strings.Join(ids, ", "))
+		}
+		severity, err := ParseSeverity(raw[id])
+		if err != nil {
+			return nil, fmt.Errorf("lint rule %s: %w", id, err)
+		}
+		cfg[id] = severity
+	}
+	return cfg, nil
+}
+
+func knownRule(id string) bool {
+	for _, rule := range rules {
+		if rule.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint runs the enabled rules against doc
+func Lint(doc *openapi3.T, cfg Config) []Issue {
+	var issues []Issue
+	for _, rule := range rules {
+		severity := defaultSeverity(rule, doc)
+		if override, ok := cfg[rule.ID]; ok {
+			severity = override
+		}
+		if severity == Off {
+			continue
+		}
+		rule.check(doc, func(location, format string, args ...interface{}) {
+			issues = append(issues, Issue{
+				Rule:     rule.ID,
+				Severity: severity,
+				Location: location,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		})
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity > issues[j].Severity
+	})
+	return issues
+}
+
+// Count returns how many issues have the given severity
+func Count(issues []Issue, severity Severity) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteText renders issues for a terminal
+func WriteText(w io.Writer, issues []Issue) error {
+	var b strings.Builder
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "  %-7s %s: %s [%s]\n", issue.Severity, issue.Location, issue.Message, issue.Rule)
+	}
+	fmt.Fprintf(&b, "%d errors, %d warnings, %d infos\n", Count(issues, Error), Count(issues, Warning), Count(issues, Info))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
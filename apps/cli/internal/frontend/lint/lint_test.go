@@ -0,0 +1,172 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"radas/internal/frontend/parser"
+)
+
+const lintSpec = `openapi: 3.0.3
+info: {title: lint, version: "1"}
+tags:
+  - name: pets
+paths:
+  /pets:
+    get:
+      operationId: pets_listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    id: {type: integer}
+    post:
+      operationId: store_createPet
+      tags: [pets]
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses:
+        "201": {description: created}
+  /pets/{id}:
+    get:
+      operationId: Get-Pet
+      tags: [animals]
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+    delete:
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "404": {description: not found}
+  /owners:
+    get:
+      operationId: pets_listPets
+      tags: [pets]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Owner"}}
+components:
+  schemas:
+    NewPet:
+      type: object
+      properties:
+        name: {type: string}
+    Pet:
+      allOf:
+        - {$ref: "#/components/schemas/NewPet"}
+        - {type: object, properties: {owner: {$ref: "#/components/schemas/Owner"}}}
+    Owner:
+      type: object
+      properties:
+        name: {type: string}
+    Legacy:
+      type: object
+      properties:
+        name: {type: string}
+`
+
+func TestLint(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(lintSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := parser.LoadOpenAPI(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]Severity)
+	for _, issue := range Lint(doc, nil) {
+		got[issue.Rule+" "+issue.Location] = issue.Severity
+	}
+	want := map[string]Severity{
+		"operation-id-missing DELETE /pets/{id}":       Error,
+		"operation-id-unique GET /pets":                Error,
+		"operation-id-naming GET /pets/{id}":           Warning,
+		"tag-namespace POST /pets":                     Warning,
+		"tag-namespace GET /pets/{id}":                 Warning,
+		"tag-namespace DELETE /pets/{id}":              Warning,
+		"response-schema POST /pets":                   Warning,
+		"response-schema DELETE /pets/{id}":            Warning,
+		"inline-schema GET /pets":                      Warning,
+		"unused-component #/components/schemas/Legacy": Warning,
+		"openapi-valid spec":                           Error, // kin-openapi rejects duplicate ids too
+	}
+	for key, severity := range want {
+		if got[key] != severity {
+			t.Errorf("%s: severity = %v, want %v", key, got[key], severity)
+		}
+	}
+	for _, unexpected := range []string{
+		"unused-component #/components/schemas/Owner",
+		"unused-component #/components/schemas/NewPet",
+	} {
+		if _, ok := got[unexpected]; ok {
+			t.Errorf("unexpected issue %s", unexpected)
+		}
+	}
+
+	cfg, err := ParseConfig(map[string]string{"unused-component": "off", "inline-schema": "error"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range Lint(doc, cfg) {
+		if issue.Rule == "unused-component" {
+			t.Errorf("disabled rule reported %v", issue)
+		}
+		if issue.Rule == "inline-schema" && issue.Severity != Error {
+			t.Errorf("inline-schema severity = %v, want error", issue.Severity)
+		}
+	}
+	if _, err := ParseConfig(map[string]string{"no-such-rule": "error"}); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+	// gen-api only warns about validation errors in 3.1 specs
+	specPath = filepath.Join(t.TempDir(), "spec31.yaml")
+	if err := os.WriteFile(specPath, []byte(strings.Replace(lintSpec, "openapi: 3.0.3", "openapi: 3.1.0", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if doc, err = parser.LoadOpenAPI(specPath); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		cfg  Config
+		want Severity
+	}{
+		{nil, Warning},
+		{Config{"openapi-valid": Error}, Error},
+	} {
+		found := false
+		for _, issue := range Lint(doc, tc.cfg) {
+			if issue.Rule == "openapi-valid" {
+				found = true
+				if issue.Severity != tc.want {
+					t.Errorf("openapi-valid severity in a 3.1 spec with %v = %v, want %v", tc.cfg, issue.Severity, tc.want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("openapi-valid should still report 3.1 specs with %v", tc.cfg)
+		}
+	}
+}
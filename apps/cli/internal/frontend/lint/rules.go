@@ -0,0 +1,304 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var rules = []Rule{
+	{"openapi-valid", "the spec passes OpenAPI validation", Error, validate},
+	{"operation-id-missing", "every operation has an operationId", Error, checkOperationIDMissing},
+	{"operation-id-unique", "operationIds are unique", Error, checkOperationIDUnique},
+	{"operation-id-naming", "operationIds are lowerCamelCase with an optional namespace_ prefix", Warning, checkOperationIDNaming},
+	{"tag-namespace", "the first tag is declared and agrees with the operationId namespace", Warning, checkTagNamespace},
+	{"response-schema", "success responses declare a body schema", Warning, checkResponseSchema},
+	{"inline-schema", "request and response objects are component schemas", Warning, checkInlineSchema},
+	{"unused-component", "every component is referenced", Warning, checkUnusedComponents},
+}
+
+// defaultSeverity is the severity a rule reports at in doc. gen-api only
+// warns about validation errors in OpenAPI 3.1 specs, which kin-openapi does
+// not fully support, so the linter does too.
+func defaultSeverity(rule Rule, doc *openapi3.T) Severity {
+	if rule.ID == "openapi-valid" && strings.HasPrefix(doc.OpenAPI, "3.1") {
+		return min(rule.Severity, Warning)
+	}
+	return rule.Severity
+}
+
+// operationIDPattern matches the ids gen-api turns into clean names, e.g.
+// listUsers or users_listUsers
+var operationIDPattern = regexp.MustCompile(`^([a-z][a-zA-Z0-9]*_)?[a-z][a-zA-Z0-9]*$`)
+
+// methods lists the HTTP methods in the order operations are reported
+var methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+type operation struct {
+	method string
+	path   string
+	*openapi3.Operation
+}
+
+func (o operation) location() string {
+	return o.method + " " + o.path
+}
+
+func operations(doc *openapi3.T) []operation {
+	if doc.Paths == nil {
+		return nil
+	}
+	var ops []operation
+	items := doc.Paths.Map()
+	for _, path := range sortedKeys(items) {
+		for _, method := range methods {
+			if op := items[path].GetOperation(method); op != nil {
+				ops = append(ops, operation{method, path, op})
+			}
+		}
+	}
+	return ops
+}
+
+// validate reports the errors kin-openapi finds, which gen-api refuses in
+// OpenAPI 3.0 specs
+func validate(doc *openapi3.T, report func(string, string, ...interface{})) {
+	if err := doc.Validate(context.Background()); err != nil {
+		report("spec", "%v", err)
+	}
+}
+
+func checkOperationIDMissing(doc *openapi3.T, report func(string, string, ...interface{})) {
+	for _, op := range operations(doc) {
+		if op.OperationID == "" {
+			report(op.location(), "operation has no operationId, so the generated client has no stable name for it")
+		}
+	}
+}
+
+func checkOperationIDUnique(doc *openapi3.T, report func(string, string, ...interface{})) {
+	seen := make(map[string]string)
+	for _, op := range operations(doc) {
+		if op.OperationID == "" {
+			continue
+		}
+		if first, ok := seen[op.OperationID]; ok {
+			report(op.location(), "operationId %q is already used by %s", op.OperationID, first)
+			continue
+		}
+		seen[op.OperationID] = op.location()
+	}
+}
+
+func checkOperationIDNaming(doc *openapi3.T, report func(string, string, ...interface{})) {
+	for _, op := range operations(doc) {
+		if op.OperationID != "" && !operationIDPattern.MatchString(op.OperationID) {
+			report(op.location(), "operationId %q should be lowerCamelCase, optionally prefixed with a namespace as in users_listUsers", op.OperationID)
+		}
+	}
+}
+
+// checkTagNamespace mirrors how gen-api picks a namespace: the first tag,
+// overridden by the prefix of a namespace_operation id
+func checkTagNamespace(doc *openapi3.T, report func(string, string, ...interface{})) {
+	declared := make(map[string]bool, len(doc.Tags))
+	for _, tag := range doc.Tags {
+		declared[tag.Name] = true
+	}
+	for _, op := range operations(doc) {
+		prefix := ""
+		if i := strings.Index(op.OperationID, "_"); i > 0 {
+			prefix = op.OperationID[:i]
+		}
+		switch {
+		case len(op.Tags) == 0 && prefix == "":
+			report(op.location(), "operation has no tag or namespace prefix, so it is generated outside any namespace")
+			continue
+		case len(op.Tags) > 0 && prefix != "" && op.Tags[0] != prefix:
+			report(op.location(), "operationId namespace %q does not match the first tag %q; gen-api uses %q", prefix, op.Tags[0], prefix)
+		}
+		if len(op.Tags) > 0 && len(declared) > 0 && !declared[op.Tags[0]] {
+			report(op.location(), "tag %q is not declared in the top-level tags", op.Tags[0])
+		}
+	}
+}
+
+func checkResponseSchema(doc *openapi3.T, report func(string, string, ...interface{})) {
+	for _, op := range operations(doc) {
+		if op.Responses == nil {
+			continue
+		}
+		responses := op.Responses.Map()
+		success := false
+		for _, status := range sortedKeys(responses) {
+			if !isSuccess(status) {
+				continue
+			}
+			success = true
+			response := responses[status].Value
+			if response == nil || status == "204" || status == "205" || op.method == "HEAD" {
+				continue
+			}
+			if len(response.Content) == 0 {
+				report(op.location(), "response %s has no content, so the client cannot type its result", status)
+				continue
+			}
+			if media := jsonMedia(response.Content); media != nil && media.Schema == nil {
+				report(op.location(), "response %s has no schema", status)
+			}
+		}
+		if !success && responses["default"] == nil {
+			report(op.location(), "operation declares no success response")
+		}
+	}
+}
+
+func checkInlineSchema(doc *openapi3.T, report func(string, string, ...interface{})) {
+	for _, op := range operations(doc) {
+		if op.RequestBody != nil && op.RequestBody.Ref == "" && op.RequestBody.Value != nil {
+			if media := jsonMedia(op.RequestBody.Value.Content); media != nil {
+				if found, where := inlineObject(media.Schema); found {
+					report(op.location(), "inline object schema in the request body%s; move it to components/schemas to get a named type", where)
+				}
+			}
+		}
+		if op.Responses == nil {
+			continue
+		}
+		responses := op.Responses.Map()
+		for _, status := range sortedKeys(responses) {
+			response := responses[status]
+			if response.Ref != "" || response.Value == nil {
+				continue
+			}
+			if media := jsonMedia(response.Value.Content); media != nil {
+				if found, where := inlineObject(media.Schema); found {
+					report(op.location(), "inline object schema in response %s%s; move it to components/schemas to get a named type", status, where)
+				}
+			}
+		}
+	}
+}
+
+// inlineObject reports whether schema, or the items of an array schema, is an
+// object with properties declared in place rather than referenced
+func inlineObject(schema *openapi3.SchemaRef) (bool, string) {
+	if schema == nil || schema.Ref != "" || schema.Value == nil {
+		return false, ""
+	}
+	if len(schema.Value.Properties) > 0 {
+		return true, ""
+	}
+	if found, _ := inlineObject(schema.Value.Items); found {
+		return true, " (array items)"
+	}
+	return false, ""
+}
+
+func isSuccess(status string) bool {
+	return strings.HasPrefix(strings.ToUpper(status), "2")
+}
+
+// jsonMedia returns the JSON media type of content, if any
+func jsonMedia(content openapi3.Content) *openapi3.MediaType {
+	if media := content.Get("application/json"); media != nil {
+		return media
+	}
+	for _, mediaType := range sortedKeys(content) {
+		if strings.Contains(mediaType, "json") {
+			return content[mediaType]
+		}
+	}
+	return nil
+}
+
+// checkUnusedComponents reports components that cannot be reached through
+// $refs from any path
+func checkUnusedComponents(doc *openapi3.T, report func(string, string, ...interface{})) {
+	if doc.Components == nil || doc.Paths == nil {
+		return
+	}
+	components := componentIndex(doc.Components)
+
+	used := make(map[string]bool)
+	queue := collectRefs(doc.Paths)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		if used[ref] {
+			continue
+		}
+		used[ref] = true
+		if component, ok := components[ref]; ok {
+			queue = append(queue, collectRefs(component)...)
+		}
+	}
+
+	for _, ref := range sortedKeys(components) {
+		if !used[ref] {
+			report(ref, "component is never referenced")
+		}
+	}
+}
+
+// componentIndex maps the local reference of every referenceable component
+// to the component
+func componentIndex(c *openapi3.Components) map[string]interface{} {
+	index := make(map[string]interface{})
+	add := func(kind, name string, component interface{}) {
+		index["#/components/"+kind+"/"+strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")] = component
+	}
+	for name, component := range c.Schemas {
+		add("schemas", name, component)
+	}
+	for name, component := range c.Parameters {
+		add("parameters", name, component)
+	}
+	for name, component := range c.RequestBodies {
+		add("requestBodies", name, component)
+	}
+	for name, component := range c.Responses {
+		add("responses", name, component)
+	}
+	for name, component := range c.Headers {
+		add("headers", name, component)
+	}
+	for name, component := range c.Examples {
+		add("examples", name, component)
+	}
+	return index
+}
+
+// collectRefs returns the local $refs that appear anywhere in v
+func collectRefs(v interface{}) []string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil
+	}
+	var refs []string
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch node := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := node["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/") {
+				refs = append(refs, ref)
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(tree)
+	return refs
+}
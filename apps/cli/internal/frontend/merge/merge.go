@@ -0,0 +1,485 @@
+// Package merge combines the OpenAPI specs of several services into one
+// contract. Components and operationIds of each service are prefixed with
+// its namespace using the namespace_Name convention the parser splits on.
+package merge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
+	"radas/internal/frontend/parser"
+)
+
+// Source is one service spec and the namespace its names are prefixed with
+type Source struct {
+	Namespace  string
+	Path       string
+	PathPrefix string // prepended to every path of the service, e.g. /users
+}
+
+// Strategy decides what happens when two sources declare the same thing
+type Strategy string
+
+const (
+	StrategyError Strategy = "error" // fail and list the conflicts
+	StrategyFirst Strategy = "first" // keep the declaration of the earlier source
+	StrategyLast  Strategy = "last"  // let later sources override earlier ones
+)
+
+// ParseStrategy validates a strategy name, defaulting to StrategyError
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(s) {
+	case "", StrategyError:
+		return StrategyError, nil
+	case StrategyFirst, StrategyLast:
+		return Strategy(s), nil
+	}
+	return "", fmt.Errorf("unknown merge strategy %q (use error, first or last)", s)
+}
+
+// Options configures the merged document
+type Options struct {
+	Strategy Strategy
+	Title    string // info.title of the merged document, defaults to the first source's
+	Version  string // info.version of the merged document, defaults to the first source's
+}
+
+// Conflict is an operation or component declared differently by several sources
+type Conflict struct {
+	Kind    string   // operation or component
+	Name    string   // e.g. GET /users or #/components/securitySchemes/bearer
+	Sources []string // namespaces declaring it, in source order
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s is declared by %s", c.Kind, c.Name, strings.Join(c.Sources, " and "))
+}
+
+// Result is the merged document and the conflicts resolved by the strategy
+type Result struct {
+	Document  map[string]interface{}
+	Conflicts []Conflict
+}
+
+// namespacePattern keeps namespaces free of the underscore the parser splits on
+var namespacePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// prefixedKinds lists the component kinds whose names are prefixed. Security
+// schemes are referenced by name rather than $ref and are shared instead.
+var prefixedKinds = []string{"schemas", "parameters", "requestBodies", "responses", "headers", "examples", "links", "callbacks"}
+
+var methods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// Merge combines sources into one OpenAPI document
+func Merge(sources []Source, opts Options) (*Result, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no specs to merge")
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = StrategyError
+	}
+	seen := make(map[string]bool)
+	for _, src := range sources {
+		if !namespacePattern.MatchString(src.Namespace) {
+			return nil, fmt.Errorf("invalid namespace %q for %s: use letters and digits only", src.Namespace, src.Path)
+		}
+		if seen[src.Namespace] {
+			return nil, fmt.Errorf("namespace %q is used by several sources", src.Namespace)
+		}
+		seen[src.Namespace] = true
+	}
+
+	m := &merger{
+		strategy:   opts.Strategy,
+		paths:      make(map[string]map[string]interface{}),
+		components: make(map[string]map[string]interface{}),
+		owners:     make(map[string]string),
+		tagIndex:   make(map[string]bool),
+	}
+	var first map[string]interface{}
+	for _, src := range sources {
+		doc, err := load(src.Path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src.Namespace, err)
+		}
+		if first == nil {
+			first = doc
+		} else if minor(doc) != minor(first) {
+			return nil, fmt.Errorf("%s is OpenAPI %v but %s is OpenAPI %v, convert them to the same version first",
+				src.Namespace, doc["openapi"], sources[0].Namespace, first["openapi"])
+		}
+		prefix(doc, src.Namespace)
+		m.add(doc, src)
+	}
+
+	if len(m.unresolved) > 0 {
+		lines := make([]string, 0, len(m.unresolved))
+		for _, conflict := range m.unresolved {
+			lines = append(lines, "  "+conflict.String())
+		}
+		return nil, fmt.Errorf("conflicting declarations, rename them or pick a merge strategy (first or last):\n%s", strings.Join(lines, "\n"))
+	}
+	return &Result{Document: m.document(first, opts), Conflicts: m.conflicts}, nil
+}
+
+// minor returns the major.minor OpenAPI version of doc
+func minor(doc map[string]interface{}) string {
+	version, _ := doc["openapi"].(string)
+	if parts := strings.SplitN(version, ".", 3); len(parts) >= 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return version
+}
+
+// load reads a spec as a JSON tree, converting Swagger 2.0 and pulling
+// external references into components so the merged file stands alone
+func load(specPath string) (map[string]interface{}, error) {
+	doc, err := parser.LoadOpenAPI(specPath)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := toTree(doc)
+	if err != nil || !hasExternalRefs(tree) {
+		return tree, err
+	}
+	if err := internalize(doc); err != nil {
+		return nil, err
+	}
+	if tree, err = toTree(doc); err != nil {
+		return nil, err
+	}
+	if hasExternalRefs(tree) {
+		return nil, fmt.Errorf("external $refs in %s could not be bundled", specPath)
+	}
+	return tree, nil
+}
+
+// internalize moves externally referenced schemas into components;
+// kin-openapi panics on references it cannot name
+func internalize(doc *openapi3.T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to bundle external $refs: %v", r)
+		}
+	}()
+	doc.InternalizeRefs(context.Background(), nil)
+	return nil
+}
+
+func toTree(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	return tree, nil
+}
+
+func hasExternalRefs(tree interface{}) bool {
+	external := false
+	walk(tree, func(node map[string]interface{}) {
+		if ref, ok := node["$ref"].(string); ok && !strings.HasPrefix(ref, "#") {
+			external = true
+		}
+	})
+	return external
+}
+
+// walk calls visit for every object in tree
+func walk(tree interface{}, visit func(map[string]interface{})) {
+	switch node := tree.(type) {
+	case map[string]interface{}:
+		visit(node)
+		for _, child := range node {
+			walk(child, visit)
+		}
+	case []interface{}:
+		for _, child := range node {
+			walk(child, visit)
+		}
+	}
+}
+
+// prefix renames the components and operationIds of doc into namespace.
+// Names that already carry the prefix are kept.
+func prefix(doc map[string]interface{}, namespace string) {
+	renames := make(map[string]string)
+	components, _ := doc["components"].(map[string]interface{})
+	for _, kind := range prefixedKinds {
+		group, ok := components[kind].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		renamed := make(map[string]interface{}, len(group))
+		for name, component := range group {
+			newName := prefixed(namespace, name)
+			renamed[newName] = component
+			renames[pointer(kind, name)] = pointer(kind, newName)
+		}
+		components[kind] = renamed
+	}
+
+	// Only $refs and discriminator mappings hold component pointers; other
+	// strings such as descriptions and examples are left as written
+	walk(doc, func(node map[string]interface{}) {
+		if ref, ok := node["$ref"].(string); ok {
+			if target, ok := renames[ref]; ok {
+				node["$ref"] = target
+			}
+		}
+		discriminator, _ := node["discriminator"].(map[string]interface{})
+		mapping, _ := discriminator["mapping"].(map[string]interface{})
+		for value, target := range mapping {
+			target, _ := target.(string)
+			if renamed, ok := renames[target]; ok {
+				mapping[value] = renamed
+			} else if renamed, ok := renames[pointer("schemas", target)]; ok {
+				// A mapping may name the schema instead of pointing at it
+				mapping[value] = strings.TrimPrefix(renamed, "#/components/schemas/")
+			}
+		}
+	})
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, item := range paths {
+		item, _ := item.(map[string]interface{})
+		for method, op := range item {
+			op, ok := op.(map[string]interface{})
+			if !ok || !methods[method] {
+				continue
+			}
+			if id, ok := op["operationId"].(string); ok && id != "" {
+				op["operationId"] = prefixed(namespace, id)
+			}
+			if tags, _ := op["tags"].([]interface{}); len(tags) == 0 {
+				op["tags"] = []interface{}{namespace}
+			}
+		}
+	}
+}
+
+func prefixed(namespace, name string) string {
+	if strings.HasPrefix(name, namespace+"_") {
+		return name
+	}
+	return namespace + "_" + name
+}
+
+func pointer(kind, name string) string {
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+	return "#/components/" + kind + "/" + name
+}
+
+type merger struct {
+	strategy   Strategy
+	paths      map[string]map[string]interface{}
+	components map[string]map[string]interface{} // kind -> name -> component
+	owners     map[string]string                 // declaration -> namespace that owns it
+	tags       []interface{}
+	tagIndex   map[string]bool
+	conflicts  []Conflict // resolved by the strategy
+	unresolved []Conflict
+}
+
+// claim records that namespace declares name and reports whether its
+// declaration should replace the current one
+func (m *merger) claim(kind, name, namespace string) bool {
+	key := kind + " " + name
+	owner, ok := m.owners[key]
+	if !ok {
+		m.owners[key] = namespace
+		return true
+	}
+	conflict := Conflict{Kind: kind, Name: name, Sources: []string{owner, namespace}}
+	switch m.strategy {
+	case StrategyFirst:
+		m.conflicts = append(m.conflicts, conflict)
+		return false
+	case StrategyLast:
+		m.conflicts = append(m.conflicts, conflict)
+		m.owners[key] = namespace
+		return true
+	}
+	m.unresolved = append(m.unresolved, conflict)
+	return false
+}
+
+func (m *merger) add(doc map[string]interface{}, src Source) {
+	pathPrefix := strings.TrimRight(src.PathPrefix, "/")
+	paths, _ := doc["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]interface{})
+		fullPath := pathPrefix + path
+		target, ok := m.paths[fullPath]
+		if !ok {
+			target = make(map[string]interface{})
+			m.paths[fullPath] = target
+		}
+		shared, _ := item["parameters"].([]interface{})
+		for _, key := range sortedKeys(item) {
+			value := item[key]
+			switch {
+			case methods[key]:
+				// Path-level parameters are moved into the operations so services
+				// sharing a path keep their own
+				op, _ := value.(map[string]interface{})
+				op["parameters"] = mergeParameters(shared, op["parameters"])
+				if len(op["parameters"].([]interface{})) == 0 {
+					delete(op, "parameters")
+				}
+				if m.claim("operation", strings.ToUpper(key)+" "+fullPath, src.Namespace) {
+					target[key] = op
+				}
+			case key == "parameters":
+			default:
+				if _, exists := target[key]; !exists {
+					target[key] = value
+				}
+			}
+		}
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	for _, kind := range sortedKeys(components) {
+		group, _ := components[kind].(map[string]interface{})
+		if m.components[kind] == nil {
+			m.components[kind] = make(map[string]interface{})
+		}
+		for _, name := range sortedKeys(group) {
+			existing, exists := m.components[kind][name]
+			if exists && reflect.DeepEqual(existing, group[name]) {
+				continue
+			}
+			if m.claim("component", pointer(kind, name), src.Namespace) {
+				m.components[kind][name] = group[name]
+			}
+		}
+	}
+
+	tags, _ := doc["tags"].([]interface{})
+	for _, tag := range tags {
+		name, _ := tag.(map[string]interface{})["name"].(string)
+		if name != "" && !m.tagIndex[name] {
+			m.tagIndex[name] = true
+			m.tags = append(m.tags, tag)
+		}
+	}
+}
+
+// mergeParameters returns the operation parameters followed by the shared
+// path-level parameters the operation does not override
+func mergeParameters(shared []interface{}, own interface{}) []interface{} {
+	params, _ := own.([]interface{})
+	merged := append([]interface{}(nil), params...)
+	declared := make(map[string]bool)
+	for _, param := range params {
+		declared[parameterKey(param)] = true
+	}
+	for _, param := range shared {
+		if key := parameterKey(param); key == "" || !declared[key] {
+			merged = append(merged, param)
+		}
+	}
+	return merged
+}
+
+func parameterKey(param interface{}) string {
+	object, _ := param.(map[string]interface{})
+	if ref, ok := object["$ref"].(string); ok {
+		return ref
+	}
+	in, _ := object["in"].(string)
+	name, _ := object["name"].(string)
+	return in + " " + name
+}
+
+func (m *merger) document(first map[string]interface{}, opts Options) map[string]interface{} {
+	info := make(map[string]interface{})
+	if firstInfo, ok := first["info"].(map[string]interface{}); ok {
+		for key, value := range firstInfo {
+			info[key] = value
+		}
+	}
+	if opts.Title != "" {
+		info["title"] = opts.Title
+	}
+	if opts.Version != "" {
+		info["version"] = opts.Version
+	}
+
+	paths := make(map[string]interface{}, len(m.paths))
+	for path, item := range m.paths {
+		paths[path] = item
+	}
+	doc := map[string]interface{}{
+		"openapi": first["openapi"],
+		"info":    info,
+		"paths":   paths,
+	}
+	for _, key := range []string{"servers", "security"} {
+		if value, ok := first[key]; ok {
+			doc[key] = value
+		}
+	}
+	if len(m.tags) > 0 {
+		doc["tags"] = m.tags
+	}
+	components := make(map[string]interface{})
+	for kind, group := range m.components {
+		if len(group) > 0 {
+			components[kind] = group
+		}
+	}
+	if len(components) > 0 {
+		doc["components"] = components
+	}
+	return doc
+}
+
+// Write saves the merged document as YAML when outputPath ends in .yaml or
+// .yml and as JSON otherwise
+func (r *Result) Write(outputPath string) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(r.Document)
+	default:
+		data, err = json.MarshalIndent(r.Document, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode merged spec: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write merged spec: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
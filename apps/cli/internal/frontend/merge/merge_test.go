@@ -0,0 +1,203 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"radas/internal/frontend/parser"
+)
+
+const usersSpec = `openapi: 3.0.3
+info: {title: users, version: "1.0.0"}
+paths:
+  /users/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: getUser
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+        "404":
+          description: missing
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Error"}
+  /health:
+    get:
+      operationId: health
+      responses:
+        "204": {description: ok}
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+  schemas:
+    User:
+      type: object
+      properties:
+        id: {type: string}
+    Error:
+      type: object
+      properties:
+        message: {type: string}
+`
+
+const ordersSpec = `openapi: 3.0.3
+info: {title: orders, version: "2.0.0"}
+paths:
+  /orders:
+    get:
+      operationId: listOrders
+      tags: [orders]
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Order"}}
+        default:
+          description: error
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Error"}
+  /health:
+    get:
+      operationId: health
+      responses:
+        "200": {description: ok}
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+  schemas:
+    Order:
+      type: object
+      properties:
+        id: {type: string}
+        buyer: {type: string}
+    Error:
+      type: object
+      properties:
+        code: {type: integer}
+`
+
+func writeSpecs(t *testing.T) []Source {
+	t.Helper()
+	dir := t.TempDir()
+	var sources []Source
+	for namespace, content := range map[string]string{"users": usersSpec, "orders": ordersSpec} {
+		specPath := filepath.Join(dir, namespace+".yaml")
+		if err := os.WriteFile(specPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, Source{Namespace: namespace, Path: specPath})
+	}
+	// Keep users first regardless of map order
+	if sources[0].Namespace != "users" {
+		sources[0], sources[1] = sources[1], sources[0]
+	}
+	return sources
+}
+
+func TestMergeConflicts(t *testing.T) {
+	sources := writeSpecs(t)
+
+	_, err := Merge(sources, Options{})
+	if err == nil || !strings.Contains(err.Error(), "operation GET /health is declared by users and orders") {
+		t.Fatalf("expected a conflict on GET /health, got %v", err)
+	}
+
+	result, err := Merge(sources, Options{Strategy: StrategyLast})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 {
+		t.Errorf("conflicts = %v, want only GET /health", result.Conflicts)
+	}
+	health := result.Document["paths"].(map[string]interface{})["/health"].(map[string]interface{})["get"].(map[string]interface{})
+	if health["operationId"] != "orders_health" {
+		t.Errorf("last strategy kept %v", health["operationId"])
+	}
+}
+
+func TestMergePrefixesNames(t *testing.T) {
+	sources := writeSpecs(t)
+	sources[1].PathPrefix = "/shop/"
+
+	result, err := Merge(sources, Options{Strategy: StrategyFirst, Title: "Platform"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(t.TempDir(), "merged-api.yaml")
+	if err := result.Write(output); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := parser.ParseOpenAPI(output)
+	if err != nil {
+		t.Fatalf("merged spec does not parse: %v", err)
+	}
+	ops := make(map[string]parser.Operation)
+	for _, op := range spec.Operations {
+		ops[op.Method+" "+op.Path] = op
+	}
+	getUser, ok := ops["GET /users/{id}"]
+	if !ok || getUser.ID != "users_getUser" || getUser.Namespace != "users" {
+		t.Errorf("GET /users/{id} = %+v", getUser)
+	}
+	if len(getUser.Parameters) != 1 || getUser.Parameters[0].Name != "id" {
+		t.Errorf("path-level parameters should move into the operation, got %+v", getUser.Parameters)
+	}
+	if op, ok := ops["GET /shop/orders"]; !ok || op.Namespace != "orders" || op.Responses["200"].Def.Items.Ref != "Order" {
+		t.Errorf("GET /shop/orders = %+v", op)
+	}
+	if op := ops["GET /health"]; op.ID != "users_health" {
+		t.Errorf("first strategy kept %s", op.ID)
+	}
+
+	names := make(map[string]bool)
+	for _, schema := range spec.Schemas {
+		names[schema.Name] = true
+	}
+	for _, name := range []string{"User", "Order", "UsersError", "OrdersError"} {
+		if !names[name] {
+			t.Errorf("missing schema %s in %v", name, names)
+		}
+	}
+	if ref := getUser.Responses["404"].Def.Ref; ref != "UsersError" {
+		t.Errorf("404 response refers to %q, want UsersError", ref)
+	}
+}
+
+func TestPrefixRewritesOnlyPointers(t *testing.T) {
+	doc := map[string]interface{}{
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Dog": map[string]interface{}{"type": "object"},
+				"Pet": map[string]interface{}{
+					"description":   "#/components/schemas/Dog",
+					"example":       map[string]interface{}{"kind": "#/components/schemas/Dog"},
+					"oneOf":         []interface{}{map[string]interface{}{"$ref": "#/components/schemas/Dog"}},
+					"discriminator": map[string]interface{}{"propertyName": "kind", "mapping": map[string]interface{}{"dog": "#/components/schemas/Dog", "puppy": "Dog"}},
+				},
+			},
+		},
+	}
+	prefix(doc, "pets")
+
+	pet := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})["pets_Pet"].(map[string]interface{})
+	if ref := pet["oneOf"].([]interface{})[0].(map[string]interface{})["$ref"]; ref != "#/components/schemas/pets_Dog" {
+		t.Errorf("$ref = %v, want #/components/schemas/pets_Dog", ref)
+	}
+	mapping := pet["discriminator"].(map[string]interface{})["mapping"].(map[string]interface{})
+	if mapping["dog"] != "#/components/schemas/pets_Dog" || mapping["puppy"] != "pets_Dog" {
+		t.Errorf("discriminator mapping = %v", mapping)
+	}
+	if pet["description"] != "#/components/schemas/Dog" || pet["example"].(map[string]interface{})["kind"] != "#/components/schemas/Dog" {
+		t.Errorf("strings that are not pointers should be kept: description = %v, example = %v", pet["description"], pet["example"])
+	}
+}
@@ -0,0 +1,224 @@
+package mock
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"radas/internal/frontend/parser"
+)
+
+// maxDepth bounds recursive schemas; deeper optional properties are left out
+// and arrays are kept at their minimum length
+const maxDepth = 4
+
+var words = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+// fakeEpoch anchors generated dates so output only depends on the seed
+var fakeEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// faker generates values that satisfy a schema from a seeded source
+type faker struct {
+	rnd     *rand.Rand
+	schemas map[string]*parser.SchemaDef
+}
+
+func (f *faker) value(def *parser.SchemaDef, depth int) interface{} {
+	if def == nil {
+		return nil
+	}
+	if def.Ref != "" {
+		if depth > maxDepth*2 {
+			return nil
+		}
+		return f.value(f.schemas[def.Ref], depth+1)
+	}
+	if def.Example != nil {
+		return def.Example
+	}
+	if len(def.Enum) > 0 {
+		return def.Enum[f.rnd.IntN(len(def.Enum))]
+	}
+
+	switch {
+	case len(def.AllOf) > 0:
+		merged := make(map[string]interface{})
+		for _, member := range def.AllOf {
+			if object, ok := f.value(member, depth).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		if object, ok := f.object(def, depth).(map[string]interface{}); ok {
+			for key, value := range object {
+				merged[key] = value
+			}
+		}
+		return merged
+	case len(def.OneOf) > 0 || len(def.AnyOf) > 0:
+		return f.variant(def, depth)
+	}
+
+	switch def.Type {
+	case "string":
+		return f.string(def)
+	case "integer":
+		min, max := bounds(def, 1, 1000)
+		min, max = math.Ceil(min), math.Floor(max)
+		if max < min {
+			return int64(min)
+		}
+		return int64(min) + f.rnd.Int64N(int64(max-min)+1)
+	case "number":
+		min, max := bounds(def, 0, 1000)
+		return math.Round((min+f.rnd.Float64()*(max-min))*100) / 100
+	case "boolean":
+		return f.rnd.IntN(2) == 1
+	case "array":
+		return f.array(def, depth)
+	case "object":
+		return f.object(def, depth)
+	}
+	return nil
+}
+
+// bounds returns the numeric range of def, nudging exclusive limits inwards
+func bounds(def *parser.SchemaDef, min, max float64) (float64, float64) {
+	if def.Minimum != nil {
+		min = *def.Minimum
+		if def.ExclusiveMinimum {
+			min++
+		}
+		if def.Maximum == nil {
+			max = min + 1000
+		}
+	}
+	if def.Maximum != nil {
+		max = *def.Maximum
+		if def.ExclusiveMaximum {
+			max--
+		}
+		if def.Minimum == nil && max < min {
+			min = max - 1000
+		}
+	}
+	return min, max
+}
+
+func (f *faker) string(def *parser.SchemaDef) string {
+	switch def.Format {
+	case "date-time":
+		return fakeEpoch.Add(time.Duration(f.rnd.IntN(365*24*3600)) * time.Second).Format(time.RFC3339)
+	case "date":
+		return fakeEpoch.AddDate(0, 0, f.rnd.IntN(365)).Format("2006-01-02")
+	case "uuid":
+		b := make([]byte, 16)
+		for i := range b {
+			b[i] = byte(f.rnd.IntN(256))
+		}
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "email":
+		return fmt.Sprintf("%s.%d@example.com", f.word(), f.rnd.IntN(100))
+	case "uri", "url":
+		return "https://example.com/" + f.word()
+	case "hostname":
+		return f.word() + ".example.com"
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", f.rnd.IntN(256), f.rnd.IntN(256), f.rnd.IntN(256))
+	}
+
+	s := f.word() + " " + f.word()
+	if def.MinLength != nil {
+		for uint64(len(s)) < *def.MinLength {
+			s += " " + f.word()
+		}
+	}
+	if def.MaxLength != nil && uint64(len(s)) > *def.MaxLength {
+		s = strings.TrimSpace(s[:*def.MaxLength])
+	}
+	return s
+}
+
+func (f *faker) word() string {
+	return words[f.rnd.IntN(len(words))]
+}
+
+func (f *faker) array(def *parser.SchemaDef, depth int) []interface{} {
+	min, max := 1, 3
+	if def.MinItems != nil {
+		min = int(*def.MinItems)
+	}
+	if def.MaxItems != nil {
+		max = int(*def.MaxItems)
+	}
+	if max < min {
+		max = min
+	}
+	n := min
+	if depth < maxDepth {
+		n += f.rnd.IntN(max - min + 1)
+	}
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, f.value(def.Items, depth+1))
+	}
+	return items
+}
+
+func (f *faker) object(def *parser.SchemaDef, depth int) interface{} {
+	object := make(map[string]interface{})
+	for _, prop := range def.Properties {
+		if depth >= maxDepth && !prop.Required {
+			continue
+		}
+		object[prop.Name] = f.value(prop.Def, depth+1)
+	}
+	if len(def.Properties) == 0 && def.AdditionalProperties != nil && depth < maxDepth {
+		for i := 0; i < 2; i++ {
+			object[f.word()] = f.value(def.AdditionalProperties, depth+1)
+		}
+	}
+	return object
+}
+
+// variant generates one of the variants and sets the discriminator property
+// to the value that maps to it
+func (f *faker) variant(def *parser.SchemaDef, depth int) interface{} {
+	variants := append(append([]*parser.SchemaDef(nil), def.OneOf...), def.AnyOf...)
+	chosen := variants[f.rnd.IntN(len(variants))]
+	value := f.value(chosen, depth)
+
+	object, ok := value.(map[string]interface{})
+	if !ok || def.Discriminator == nil {
+		return value
+	}
+	tag := chosen.Ref
+	for _, key := range sortedNames(def.Discriminator.Mapping) {
+		if def.Discriminator.Mapping[key] == chosen.Ref {
+			tag = key
+			break
+		}
+	}
+	if tag != "" {
+		object[def.Discriminator.PropertyName] = tag
+	}
+	return object
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
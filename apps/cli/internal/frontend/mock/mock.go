@@ -0,0 +1,320 @@
+// Package mock serves an OpenAPI contract over HTTP: requests are validated
+// against the operation and answered with spec examples, fixtures or fake data
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"radas/internal/frontend/parser"
+)
+
+// Options configures a mock server
+type Options struct {
+	Seed        uint64    // seed of the fake data; equal seeds give equal responses
+	FixturesDir string    // per-operation overrides stored as <operationId>.json
+	BasePath    string    // prefix every route is served under, e.g. /api
+	Log         io.Writer // receives one line per request when set
+}
+
+// Fixture overrides the response of one operation. Fixture files are read on
+// every request, so they can be edited while the server is running.
+type Fixture struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+// Server is an http.Handler answering the operations of a parsed spec
+type Server struct {
+	routes    []route
+	validator *validator
+	schemas   map[string]*parser.SchemaDef
+	opts      Options
+}
+
+type route struct {
+	op       parser.Operation
+	segments []string
+	literals int // number of literal segments, more specific routes win
+}
+
+// New builds a mock server for spec
+func New(spec *parser.ParsedSpec, opts Options) *Server {
+	schemas := make(map[string]*parser.SchemaDef, len(spec.Schemas))
+	for _, schema := range spec.Schemas {
+		schemas[schema.Name] = schema.Def
+	}
+
+	s := &Server{
+		validator: &validator{schemas: schemas},
+		schemas:   schemas,
+		opts:      opts,
+	}
+	for _, op := range spec.Operations {
+		rt := route{op: op, segments: splitPath(op.Path)}
+		for _, segment := range rt.segments {
+			if !isParamSegment(segment) {
+				rt.literals++
+			}
+		}
+		s.routes = append(s.routes, rt)
+	}
+	sort.SliceStable(s.routes, func(i, j int) bool {
+		return s.routes[i].literals > s.routes[j].literals
+	})
+	return s
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func isParamSegment(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// match returns the path parameters when the request path fits the route
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range rt.segments {
+		if isParamSegment(segment) {
+			params[segment[1:len(segment)-1]] = segments[i]
+		} else if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// statusRecorder remembers the status code for the request log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	source := s.serve(rec, r)
+	if s.opts.Log != nil {
+		fmt.Fprintf(s.opts.Log, "%s %s -> %d %s (%s)\n", r.Method, r.URL.RequestURI(), rec.status, source, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// serve answers the request and returns where the response came from
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) string {
+	// The dev server of the app usually runs on another origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "*")
+
+	requestPath := r.URL.Path
+	if s.opts.BasePath != "" {
+		base := "/" + strings.Trim(s.opts.BasePath, "/")
+		if requestPath != base && !strings.HasPrefix(requestPath, base+"/") {
+			writeError(w, http.StatusNotFound, "no route for "+requestPath)
+			return "unmatched"
+		}
+		requestPath = strings.TrimPrefix(requestPath, base)
+	}
+
+	segments := splitPath(requestPath)
+	var allowed []string
+	for _, rt := range s.routes {
+		params, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(rt.op.Method, r.Method) {
+			allowed = append(allowed, strings.ToUpper(rt.op.Method))
+			continue
+		}
+
+		if errs := s.validator.request(rt.op, r, params); len(errs) > 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"message": "Request does not match the API contract",
+				"errors":  errs,
+			})
+			return "invalid request"
+		}
+		return s.respond(w, r, rt.op)
+	}
+
+	if len(allowed) == 0 {
+		writeError(w, http.StatusNotFound, "no route for "+r.Method+" "+requestPath)
+		return "unmatched"
+	}
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return "preflight"
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, r.Method+" is not allowed for "+requestPath)
+	return "unmatched"
+}
+
+// respond writes the fixture of the operation if there is one, and otherwise
+// the example or generated body of the selected response
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, op parser.Operation) string {
+	fixture, err := s.fixture(op.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return "broken fixture"
+	}
+	if fixture != nil {
+		for name, value := range fixture.Headers {
+			w.Header().Set(name, value)
+		}
+		status := fixture.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if len(fixture.Body) == 0 {
+			w.WriteHeader(status)
+		} else {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write(fixture.Body)
+		}
+		return "fixture"
+	}
+
+	prefer := parsePrefer(r.Header.Get("Prefer"))
+	status, response, ok := selectResponse(op, prefer["code"])
+	if !ok || (response.Def == nil && len(response.Examples) == 0) {
+		w.WriteHeader(status)
+		return "empty"
+	}
+
+	if name, example, ok := selectExample(response.Examples, prefer["example"]); ok {
+		writeJSON(w, status, example)
+		return "example " + name
+	}
+	f := &faker{
+		rnd:     rand.New(rand.NewPCG(s.opts.Seed, hashString(op.ID))),
+		schemas: s.schemas,
+	}
+	writeJSON(w, status, f.value(response.Def, 0))
+	return "generated"
+}
+
+// fixture reads <FixturesDir>/<operationId>.json, returning nil when absent
+func (s *Server) fixture(operationID string) (*Fixture, error) {
+	if s.opts.FixturesDir == "" {
+		return nil, nil
+	}
+	filePath := filepath.Join(s.opts.FixturesDir, operationID+".json")
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", filePath, err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", filePath, err)
+	}
+	return &fixture, nil
+}
+
+// parsePrefer reads the Prefer header, e.g. "code=404, example=notFound"
+func parsePrefer(header string) map[string]string {
+	prefer := make(map[string]string)
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			prefer[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prefer
+}
+
+// selectResponse picks the preferred status code if the operation declares
+// it, and otherwise the lowest success status
+func selectResponse(op parser.Operation, preferred string) (int, parser.Response, bool) {
+	if response, ok := op.Responses[preferred]; ok {
+		if status, err := strconv.Atoi(preferred); err == nil {
+			return status, response, true
+		}
+	}
+
+	var codes []string
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if status, err := strconv.Atoi(code); err == nil {
+			return status, op.Responses[code], true
+		}
+		if strings.EqualFold(code, "2XX") {
+			return http.StatusOK, op.Responses[code], true
+		}
+	}
+	if response, ok := op.Responses["default"]; ok {
+		return http.StatusOK, response, true
+	}
+	return http.StatusNoContent, parser.Response{}, false
+}
+
+// selectExample returns the preferred example, then the default one, then
+// the first by name
+func selectExample(examples map[string]interface{}, preferred string) (string, interface{}, bool) {
+	for _, name := range []string{preferred, "default"} {
+		if example, ok := examples[name]; ok && name != "" {
+			return name, example, true
+		}
+	}
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", nil, false
+	}
+	sort.Strings(names)
+	return names[0], examples[names[0]], true
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
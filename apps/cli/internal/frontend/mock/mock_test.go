@@ -0,0 +1,211 @@
+package mock
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"radas/internal/frontend/parser"
+)
+
+const mockSpec = `openapi: 3.0.3
+info: {title: mock, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, minimum: 1, maximum: 100}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: "#/components/schemas/NewPet"}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+              examples:
+                rex: {value: {id: 1, name: Rex, status: available, createdAt: "2024-05-01T10:00:00Z"}}
+                tom: {value: {id: 2, name: Tom, status: adopted, createdAt: "2024-05-02T10:00:00Z"}}
+        "404":
+          description: not found
+          content:
+            application/json:
+              example: {message: no such pet}
+components:
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, minLength: 1}
+        status: {type: string, enum: [available, adopted]}
+    Pet:
+      allOf:
+        - {$ref: "#/components/schemas/NewPet"}
+        - type: object
+          required: [id, createdAt]
+          properties:
+            id: {type: integer, minimum: 1}
+            createdAt: {type: string, format: date-time}
+            tags: {type: array, items: {type: string}, maxItems: 3}
+`
+
+func parseMockSpec(t *testing.T) *parser.ParsedSpec {
+	t.Helper()
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(mockSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := parser.ParseOpenAPI(specPath)
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+	return spec
+}
+
+func newTestServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(New(parseMockSpec(t), opts))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func do(t *testing.T, method, url, body string, header http.Header) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestGeneratedResponsesAreSeeded(t *testing.T) {
+	first := newTestServer(t, Options{Seed: 7})
+	second := newTestServer(t, Options{Seed: 7})
+	other := newTestServer(t, Options{Seed: 8})
+
+	status, body := do(t, "GET", first.URL+"/pets", "", nil)
+	if status != http.StatusOK {
+		t.Fatalf("GET /pets = %d %s", status, body)
+	}
+	if _, again := do(t, "GET", second.URL+"/pets", "", nil); again != body {
+		t.Errorf("same seed should give the same response:\n%s\n%s", body, again)
+	}
+	if _, different := do(t, "GET", other.URL+"/pets", "", nil); different == body {
+		t.Errorf("another seed should give another response")
+	}
+
+	var pets []interface{}
+	if err := json.Unmarshal([]byte(body), &pets); err != nil || len(pets) == 0 {
+		t.Fatalf("expected a list of pets, got %s", body)
+	}
+	v := New(parseMockSpec(t), Options{}).validator
+	for _, pet := range pets {
+		if errs := v.validate(&parser.SchemaDef{Ref: "Pet"}, pet, "pet"); len(errs) > 0 {
+			t.Errorf("generated pet does not match its schema: %v", errs)
+		}
+	}
+}
+
+func TestExamplesAndPrefer(t *testing.T) {
+	server := newTestServer(t, Options{})
+
+	if _, body := do(t, "GET", server.URL+"/pets/1", "", nil); !strings.Contains(body, `"Rex"`) {
+		t.Errorf("expected the first named example, got %s", body)
+	}
+	prefer := http.Header{"Prefer": {"example=tom"}}
+	if _, body := do(t, "GET", server.URL+"/pets/1", "", prefer); !strings.Contains(body, `"Tom"`) {
+		t.Errorf("expected the preferred example, got %s", body)
+	}
+	prefer = http.Header{"Prefer": {"code=404"}}
+	if status, body := do(t, "GET", server.URL+"/pets/1", "", prefer); status != http.StatusNotFound || !strings.Contains(body, "no such pet") {
+		t.Errorf("expected the 404 example, got %d %s", status, body)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	server := newTestServer(t, Options{})
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	tests := []struct {
+		name, method, path, body, want string
+	}{
+		{"query type", "GET", "/pets?limit=abc", "", "query parameter limit: expected an integer"},
+		{"query range", "GET", "/pets?limit=500", "", "query parameter limit must be less than or equal to 100"},
+		{"path type", "GET", "/pets/rex", "", "path parameter id: expected an integer"},
+		{"missing body", "POST", "/pets", "", "request body is required"},
+		{"body schema", "POST", "/pets", `{"status": "lost"}`, "body.name is required"},
+		{"body enum", "POST", "/pets", `{"name": "Rex", "status": "lost"}`, "body.status must be one of"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, tt.method, server.URL+tt.path, tt.body, jsonHeader)
+			if status != http.StatusBadRequest || !strings.Contains(body, tt.want) {
+				t.Errorf("got %d %s, want 400 with %q", status, body, tt.want)
+			}
+		})
+	}
+
+	if status, body := do(t, "POST", server.URL+"/pets", `{"name": "Rex"}`, jsonHeader); status != http.StatusCreated {
+		t.Errorf("valid request got %d %s", status, body)
+	}
+}
+
+func TestFixturesAndRouting(t *testing.T) {
+	fixtures := t.TempDir()
+	fixture := `{"status": 200, "headers": {"X-Total": "1"}, "body": [{"id": 9, "name": "Fixture"}]}`
+	if err := os.WriteFile(filepath.Join(fixtures, "listPets.json"), []byte(fixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := newTestServer(t, Options{FixturesDir: fixtures, BasePath: "/api"})
+
+	if status, body := do(t, "GET", server.URL+"/api/pets", "", nil); status != http.StatusOK || !strings.Contains(body, "Fixture") {
+		t.Errorf("expected the fixture, got %d %s", status, body)
+	}
+	if status, _ := do(t, "GET", server.URL+"/pets", "", nil); status != http.StatusNotFound {
+		t.Errorf("routes outside the base path should not match, got %d", status)
+	}
+	if status, _ := do(t, "DELETE", server.URL+"/api/pets", "", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("undeclared method should be rejected, got %d", status)
+	}
+	if status, _ := do(t, "OPTIONS", server.URL+"/api/pets", "", nil); status != http.StatusNoContent {
+		t.Errorf("CORS preflight should succeed, got %d", status)
+	}
+}
@@ -0,0 +1,371 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"radas/internal/frontend/parser"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validator checks request values against the schemas of a spec
+type validator struct {
+	schemas map[string]*parser.SchemaDef
+
+	mu       sync.Mutex // guards patterns, requests are served concurrently
+	patterns map[string]*regexp.Regexp
+}
+
+// request validates the parameters and JSON body of r against op and
+// returns one message per problem
+func (v *validator) request(op parser.Operation, r *http.Request, pathParams map[string]string) []string {
+	var errs []string
+	query := r.URL.Query()
+	for _, param := range op.Parameters {
+		var raw []string
+		switch param.In {
+		case "path":
+			if value, ok := pathParams[param.Name]; ok {
+				raw = []string{value}
+			}
+		case "query":
+			raw = query[param.Name]
+		case "header":
+			raw = r.Header.Values(param.Name)
+		case "cookie":
+			if cookie, err := r.Cookie(param.Name); err == nil {
+				raw = []string{cookie.Value}
+			}
+		}
+		errs = append(errs, v.parameter(param, raw)...)
+	}
+
+	if op.RequestBody != nil {
+		errs = append(errs, v.body(op.RequestBody, r)...)
+	}
+	return errs
+}
+
+// parameter converts the raw strings of a parameter to the declared type
+// before validating them against its schema
+func (v *validator) parameter(param parser.Parameter, raw []string) []string {
+	where := param.In + " parameter " + param.Name
+	if len(raw) == 0 || (len(raw) == 1 && raw[0] == "" && param.In != "query") {
+		if param.Required {
+			return []string{where + " is required"}
+		}
+		return nil
+	}
+
+	def := v.resolve(param.Def)
+	if def == nil {
+		return nil
+	}
+	switch def.Type {
+	case "object":
+		// Object parameters are accepted as sent
+		return nil
+	case "array":
+		items := raw
+		if len(raw) == 1 && !(param.Explode && param.In == "query") {
+			items = strings.Split(raw[0], arrayDelimiter(param.Style))
+		}
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			value, err := coerce(v.resolve(def.Items), item)
+			if err != nil {
+				return []string{fmt.Sprintf("%s: %v", where, err)}
+			}
+			values = append(values, value)
+		}
+		return v.validate(def, values, where)
+	default:
+		value, err := coerce(def, raw[0])
+		if err != nil {
+			return []string{fmt.Sprintf("%s: %v", where, err)}
+		}
+		return v.validate(def, value, where)
+	}
+}
+
+func arrayDelimiter(style string) string {
+	switch style {
+	case "spaceDelimited":
+		return " "
+	case "pipeDelimited":
+		return "|"
+	}
+	return ","
+}
+
+// coerce converts a raw parameter string to the JSON value of its schema type
+func coerce(def *parser.SchemaDef, raw string) (interface{}, error) {
+	if def == nil {
+		return raw, nil
+	}
+	switch def.Type {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got %q", raw)
+		}
+		return float64(n), nil
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", raw)
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean, got %q", raw)
+		}
+		return b, nil
+	}
+	return raw, nil
+}
+
+func (v *validator) body(body *parser.RequestBody, r *http.Request) []string {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return []string{"failed to read request body: " + err.Error()}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		if body.Required {
+			return []string{"request body is required"}
+		}
+		return nil
+	}
+	// Only JSON bodies are described by a schema
+	if body.Def == nil || !strings.Contains(r.Header.Get("Content-Type"), "json") {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return []string{"request body is not valid JSON: " + err.Error()}
+	}
+	return v.validate(body.Def, value, "body")
+}
+
+// resolve follows references to component schemas
+func (v *validator) resolve(def *parser.SchemaDef) *parser.SchemaDef {
+	for i := 0; def != nil && def.Ref != "" && i < 32; i++ {
+		def = v.schemas[def.Ref]
+	}
+	return def
+}
+
+// validate checks a decoded JSON value against def; where names the value
+// in the returned messages
+func (v *validator) validate(def *parser.SchemaDef, value interface{}, where string) []string {
+	return v.check(def, value, where, true)
+}
+
+func (v *validator) check(def *parser.SchemaDef, value interface{}, where string, strict bool) []string {
+	def = v.resolve(def)
+	if def == nil {
+		return nil
+	}
+	if value == nil {
+		if def.Nullable || def.Type == "" {
+			return nil
+		}
+		return []string{where + " must not be null"}
+	}
+
+	var errs []string
+	// allOf members only see part of the object, so they cannot reject unknown properties
+	for _, member := range def.AllOf {
+		errs = append(errs, v.check(member, value, where, false)...)
+	}
+	if len(def.OneOf) > 0 || len(def.AnyOf) > 0 {
+		variants := append(append([]*parser.SchemaDef(nil), def.OneOf...), def.AnyOf...)
+		errs = append(errs, v.variant(def, variants, value, where)...)
+	}
+	if len(def.Enum) > 0 && !inEnum(def.Enum, value) {
+		errs = append(errs, fmt.Sprintf("%s must be one of %v", where, def.Enum))
+	}
+
+	switch def.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return append(errs, where+" must be a string")
+		}
+		errs = append(errs, v.checkString(def, s, where)...)
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return append(errs, where+" must be a "+def.Type)
+		}
+		if def.Type == "integer" && n != math.Trunc(n) {
+			errs = append(errs, where+" must be an integer")
+		}
+		errs = append(errs, checkRange(def, n, where)...)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, where+" must be a boolean")
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return append(errs, where+" must be an array")
+		}
+		if def.MinItems != nil && uint64(len(items)) < *def.MinItems {
+			errs = append(errs, fmt.Sprintf("%s must have at least %d items", where, *def.MinItems))
+		}
+		if def.MaxItems != nil && uint64(len(items)) > *def.MaxItems {
+			errs = append(errs, fmt.Sprintf("%s must have at most %d items", where, *def.MaxItems))
+		}
+		for i, item := range items {
+			errs = append(errs, v.check(def.Items, item, fmt.Sprintf("%s[%d]", where, i), true)...)
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, where+" must be an object")
+		}
+		errs = append(errs, v.checkObject(def, object, where, strict)...)
+	}
+	return errs
+}
+
+// variant accepts a value matching one of the variants, using the
+// discriminator to pick the variant when there is one
+func (v *validator) variant(def *parser.SchemaDef, variants []*parser.SchemaDef, value interface{}, where string) []string {
+	if d := def.Discriminator; d != nil && len(d.Mapping) > 0 {
+		object, _ := value.(map[string]interface{})
+		tag, _ := object[d.PropertyName].(string)
+		name, ok := d.Mapping[tag]
+		if !ok {
+			return []string{fmt.Sprintf("%s.%s has unknown value %q", where, d.PropertyName, tag)}
+		}
+		return v.check(&parser.SchemaDef{Ref: name}, value, where, false)
+	}
+	for _, variant := range variants {
+		if len(v.check(variant, value, where, false)) == 0 {
+			return nil
+		}
+	}
+	return []string{where + " does not match any of the allowed schemas"}
+}
+
+func (v *validator) checkString(def *parser.SchemaDef, s, where string) []string {
+	var errs []string
+	length := uint64(utf8.RuneCountInString(s))
+	if def.MinLength != nil && length < *def.MinLength {
+		errs = append(errs, fmt.Sprintf("%s must be at least %d characters", where, *def.MinLength))
+	}
+	if def.MaxLength != nil && length > *def.MaxLength {
+		errs = append(errs, fmt.Sprintf("%s must be at most %d characters", where, *def.MaxLength))
+	}
+	if def.Pattern != "" {
+		if re := v.pattern(def.Pattern); re != nil && !re.MatchString(s) {
+			errs = append(errs, fmt.Sprintf("%s must match %s", where, def.Pattern))
+		}
+	}
+
+	var valid bool
+	switch def.Format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		valid = err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		valid = err == nil
+	case "uuid":
+		valid = uuidPattern.MatchString(s)
+	case "email":
+		valid = strings.Contains(s, "@")
+	default:
+		valid = true
+	}
+	if !valid {
+		errs = append(errs, fmt.Sprintf("%s must be a valid %s", where, def.Format))
+	}
+	return errs
+}
+
+func (v *validator) pattern(pattern string) *regexp.Regexp {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if re, ok := v.patterns[pattern]; ok {
+		return re
+	}
+	if v.patterns == nil {
+		v.patterns = make(map[string]*regexp.Regexp)
+	}
+	// ECMA patterns Go cannot compile are not enforced
+	re, _ := regexp.Compile(pattern)
+	v.patterns[pattern] = re
+	return re
+}
+
+func checkRange(def *parser.SchemaDef, n float64, where string) []string {
+	var errs []string
+	if def.Minimum != nil && (n < *def.Minimum || (def.ExclusiveMinimum && n == *def.Minimum)) {
+		errs = append(errs, fmt.Sprintf("%s must be greater than %s%v", where, orEqual(!def.ExclusiveMinimum), *def.Minimum))
+	}
+	if def.Maximum != nil && (n > *def.Maximum || (def.ExclusiveMaximum && n == *def.Maximum)) {
+		errs = append(errs, fmt.Sprintf("%s must be less than %s%v", where, orEqual(!def.ExclusiveMaximum), *def.Maximum))
+	}
+	return errs
+}
+
+func orEqual(inclusive bool) string {
+	if inclusive {
+		return "or equal to "
+	}
+	return ""
+}
+
+func (v *validator) checkObject(def *parser.SchemaDef, object map[string]interface{}, where string, strict bool) []string {
+	var errs []string
+	known := make(map[string]bool, len(def.Properties))
+	for _, prop := range def.Properties {
+		known[prop.Name] = true
+		value, ok := object[prop.Name]
+		if !ok {
+			if prop.Required {
+				errs = append(errs, where+"."+prop.Name+" is required")
+			}
+			continue
+		}
+		errs = append(errs, v.check(prop.Def, value, where+"."+prop.Name, true)...)
+	}
+
+	for _, name := range sortedNames(object) {
+		if known[name] {
+			continue
+		}
+		switch {
+		case def.AdditionalProperties != nil:
+			errs = append(errs, v.check(def.AdditionalProperties, object[name], where+"."+name, true)...)
+		case def.Strict && strict:
+			errs = append(errs, where+"."+name+" is not allowed")
+		}
+	}
+	return errs
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
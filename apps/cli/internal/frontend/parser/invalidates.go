@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// invalidatesExtension lists the queries whose cached data a mutation changes
+const invalidatesExtension = "x-radas-invalidates"
+
+// invalidatesOf reads x-radas-invalidates, a list of operationIds or
+// "METHOD /path" references; an empty list means the mutation changes nothing
+func invalidatesOf(operation *openapi3.Operation) []string {
+	declared, ok := operation.Extensions[invalidatesExtension].([]interface{})
+	if !ok {
+		return nil
+	}
+	refs := make([]string, 0, len(declared))
+	for _, ref := range declared {
+		if s, ok := ref.(string); ok && s != "" {
+			refs = append(refs, s)
+		}
+	}
+	return refs
+}
+
+// resolveInvalidates replaces "METHOD /path" references by operation ids
+// and drops references to unknown operations, or to mutations which have no
+// cached data, with a warning
+func resolveInvalidates(operations []Operation) {
+	byRef := make(map[string]*Operation, 2*len(operations))
+	for i := range operations {
+		op := &operations[i]
+		byRef[op.ID] = op
+		byRef[op.Method+" "+op.Path] = op
+	}
+	for i := range operations {
+		op := &operations[i]
+		if op.Invalidates == nil {
+			continue
+		}
+		resolved := make([]string, 0, len(op.Invalidates))
+		for _, ref := range op.Invalidates {
+			method, path, isPath := strings.Cut(strings.TrimSpace(ref), " ")
+			key := ref
+			if isPath {
+				key = strings.ToUpper(method) + " " + strings.TrimSpace(path)
+			}
+			target, ok := byRef[key]
+			if !ok {
+				fmt.Printf("⚠️ %s on %s %s: unknown operation %q\n", invalidatesExtension, op.Method, op.Path, ref)
+				continue
+			}
+			if target.Method != "GET" {
+				fmt.Printf("⚠️ %s on %s %s: %q is not a query\n", invalidatesExtension, op.Method, op.Path, ref)
+				continue
+			}
+			resolved = append(resolved, target.ID)
+		}
+		op.Invalidates = resolved
+	}
+}
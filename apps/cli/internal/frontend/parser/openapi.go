@@ -3,6 +3,7 @@ package parser
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -20,6 +21,12 @@ type Operation struct {
 	Responses   map[string]Response
 	Namespace   string
 	Entity      string
+	Security    []SecurityRequirement // effective requirements, empty when no auth is needed
+	Pagination  *Pagination           // set for paginated GET operations
+	Invalidates []string              // ids of the queries a mutation changes, from x-radas-invalidates; nil when not declared
+
+	SynthesizedID bool // the spec has no operationId, ID was derived from Method and Path
+	paginationOff bool // x-radas-pagination: false
 }
 
 type Parameter struct {
@@ -28,36 +35,101 @@ type Parameter struct {
 	Required bool
 	Schema   string
 	Type     string
+	Def      *SchemaDef
+	Style    string // serialization style with the OpenAPI default for In applied
+	Explode  bool
 }
 
 type RequestBody struct {
-	Required bool
-	Schema   string
+	Required    bool
+	Schema      string
+	Def         *SchemaDef            // schema of the ContentType media type
+	ContentType string                // media type the client sends, JSON when declared
+	Content     map[string]*SchemaDef // media type -> schema, nil when none is declared
 }
 
 type Response struct {
 	Description string
 	Schema      string
+	Def         *SchemaDef             // schema of the JSON content, if any
+	ContentType string                 // media type the client asks for, JSON when declared
+	Content     map[string]*SchemaDef  // media type -> schema, nil when none is declared
+	Examples    map[string]interface{} // examples of the JSON content by name, "default" for a lone example
 }
 
 type Schema struct {
-	Name       string
-	Type       string
-	Properties map[string]interface{}
-	Required   []string
-	Namespace  string
+	Name        string
+	Type        string
+	Required    []string
+	Namespace   string
+	Description string
+	Def         *SchemaDef
+}
+
+// SchemaDef is a recursive description of an OpenAPI schema. References to
+// component schemas are kept as Ref (the generated schema name) instead of
+// being inlined, so generators can emit named types that point at each other.
+type SchemaDef struct {
+	Ref         string
+	Type        string
+	Format      string
+	Description string
+	Nullable    bool
+	Example     interface{}
+
+	// Object
+	Properties           []Property
+	AdditionalProperties *SchemaDef
+	Strict               bool // additionalProperties: false
+
+	// Array
+	Items    *SchemaDef
+	MinItems *uint64
+	MaxItems *uint64
+
+	// Scalar constraints
+	Enum             []interface{}
+	Default          interface{}
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+	MinLength        *uint64
+	MaxLength        *uint64
+	Pattern          string
+
+	// Composition
+	AllOf         []*SchemaDef
+	OneOf         []*SchemaDef
+	AnyOf         []*SchemaDef
+	Discriminator *Discriminator
+}
+
+// Discriminator describes how the variants of a oneOf/anyOf are told apart
+type Discriminator struct {
+	PropertyName string
+	Mapping      map[string]string // discriminator value -> schema name
+}
+
+// Property is a named member of an object SchemaDef
+type Property struct {
+	Name     string
+	Required bool
+	Def      *SchemaDef
 }
 
 type ParsedSpec struct {
-	Operations []Operation
-	Schemas    []Schema
-	Namespaces map[string][]string
+	Operations      []Operation
+	Schemas         []Schema
+	Namespaces      map[string][]string
+	SecuritySchemes []SecurityScheme
 }
 
 // OpenAPIOptions contains options for parsing OpenAPI specifications
 type OpenAPIOptions struct {
-	SkipValidation bool // Skip OpenAPI validation entirely
-	ErrorsOnly     bool // Show only error level validation issues (not warnings)
+	SkipValidation bool                  // Skip OpenAPI validation entirely
+	ErrorsOnly     bool                  // Show only error level validation issues (not warnings)
+	Pagination     PaginationConventions // names that mark list operations as paginated
 }
 
 func ParseOpenAPI(specPath string, options ...OpenAPIOptions) (*ParsedSpec, error) {
@@ -73,10 +145,18 @@ func ParseOpenAPI(specPath string, options ...OpenAPIOptions) (*ParsedSpec, erro
 		IsExternalRefsAllowed: true,
 	}
 
-	doc, err := loader.LoadFromFile(specPath)
+	doc, conversion, err := loadDocument(loader, specPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
 	}
+	if conversion != nil {
+		fmt.Printf("⚠️ Swagger 2.0 spec detected. Converted to OpenAPI %s.\n", doc.OpenAPI)
+		if !opts.ErrorsOnly {
+			for _, warning := range conversion.Warnings {
+				fmt.Printf("Conversion warning: %s\n", warning)
+			}
+		}
+	}
 
 	// Handle validation based on options
 	if !opts.SkipValidation {
@@ -101,25 +181,52 @@ func ParseOpenAPI(specPath string, options ...OpenAPIOptions) (*ParsedSpec, erro
 	}
 
 	parsed := &ParsedSpec{
-		Operations: []Operation{},
-		Schemas:    []Schema{},
-		Namespaces: make(map[string][]string),
+		Operations:      []Operation{},
+		Schemas:         []Schema{},
+		Namespaces:      make(map[string][]string),
+		SecuritySchemes: extractSecuritySchemes(doc),
 	}
 
-	// Parse schemas
-	if doc.Components != nil && doc.Components.Schemas != nil {
-		for name, schemaRef := range doc.Components.Schemas {
-			schema := parseSchema(name, schemaRef.Value)
-			parsed.Schemas = append(parsed.Schemas, schema)
-		}
+	var componentSchemas openapi3.Schemas
+	if doc.Components != nil {
+		componentSchemas = doc.Components.Schemas
+	}
+	conv, err := newSchemaConverter(componentSchemas)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse operations
-	for path, pathItem := range doc.Paths.Map() {
-		operations := extractOperations(path, pathItem)
+	// Parse schemas in name order so generated output is reproducible
+	for _, name := range sortedKeys(componentSchemas) {
+		schema := parseSchema(name, componentSchemas[name], conv)
+		parsed.Schemas = append(parsed.Schemas, schema)
+	}
+
+	// Parse operations in path order
+	pathItems := doc.Paths.Map()
+	paths := make([]string, 0, len(pathItems))
+	for path := range pathItems {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		operations := extractOperations(path, pathItems[path], conv)
 		parsed.Operations = append(parsed.Operations, operations...)
 	}
 
+	uniqueSynthesizedIDs(parsed.Operations)
+
+	detectPagination(parsed, opts.Pagination)
+	resolveInvalidates(parsed.Operations)
+
+	// Operations without their own security inherit the top-level requirements
+	defaultSecurity := securityRequirements(&doc.Security)
+	for i := range parsed.Operations {
+		if parsed.Operations[i].Security == nil {
+			parsed.Operations[i].Security = defaultSecurity
+		}
+	}
+
 	// Group by namespaces
 	for _, op := range parsed.Operations {
 		if op.Namespace != "" {
@@ -130,6 +237,29 @@ func ParseOpenAPI(specPath string, options ...OpenAPIOptions) (*ParsedSpec, erro
 	return parsed, nil
 }
 
+// uniqueSynthesizedIDs numbers synthesized ids that clash with another
+// operation, keeping the declared ids untouched
+func uniqueSynthesizedIDs(operations []Operation) {
+	taken := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		if !op.SynthesizedID {
+			taken[op.ID] = true
+		}
+	}
+	for i := range operations {
+		op := &operations[i]
+		if !op.SynthesizedID {
+			continue
+		}
+		id := op.ID
+		for n := 2; taken[id]; n++ {
+			id = fmt.Sprintf("%s%d", op.ID, n)
+		}
+		op.ID = id
+		taken[id] = true
+	}
+}
+
 func getSchemaType(types *openapi3.Types) string {
 	if types == nil || len(*types) == 0 {
 		return ""
@@ -152,72 +282,268 @@ func derefString(s *string) string {
 	return *s
 }
 
-func parseSchema(name string, schema *openapi3.Schema) Schema {
-	namespace := ""
-	originalName := name
+// schemaConverter turns kin-openapi schemas into SchemaDefs, resolving
+// references to component schemas into their generated names
+type schemaConverter struct {
+	names    map[string]string // component key -> generated schema name
+	visiting map[*openapi3.Schema]bool
+}
 
-	// Extract namespace from name (e.g., "users_User" -> namespace: "users", name: "User")
-	if parts := strings.Split(name, "_"); len(parts) > 1 {
+func newSchemaConverter(schemas openapi3.Schemas) (*schemaConverter, error) {
+	names := make(map[string]string, len(schemas))
+	taken := make(map[string]int, len(schemas))
+	for key := range schemas {
+		_, name := splitSchemaName(key)
+		names[key] = name
+		taken[name]++
+	}
+	// Namespaces that declare the same name, as merged specs often do, get
+	// their namespace in front: users_Error -> UsersError
+	for key, name := range names {
+		if namespace, _ := splitSchemaName(key); taken[name] > 1 && namespace != "" {
+			names[key] = typeName(strings.ToUpper(namespace[:1]) + namespace[1:] + name)
+		}
+	}
+	// A renamed schema may still meet another, e.g. users_Error and UsersError
+	owners := make(map[string]string, len(names))
+	for _, key := range sortedKeys(schemas) {
+		if other, ok := owners[names[key]]; ok {
+			return nil, fmt.Errorf("schemas %q and %q both generate the type %s, rename one of them", other, key, names[key])
+		}
+		owners[names[key]] = key
+	}
+	return &schemaConverter{
+		names:    names,
+		visiting: make(map[*openapi3.Schema]bool),
+	}, nil
+}
+
+// splitSchemaName extracts the namespace from a component key
+// (e.g., "users_User" -> namespace: "users", name: "User")
+func splitSchemaName(key string) (string, string) {
+	namespace := ""
+	name := key
+	if parts := strings.Split(key, "_"); len(parts) > 1 {
 		namespace = parts[0]
-		originalName = strings.Join(parts[1:], "_")
+		name = strings.Join(parts[1:], "_")
 	}
+	return namespace, typeName(name)
+}
 
-	properties := make(map[string]interface{})
-	if schema.Properties != nil {
-		for propName, propRef := range schema.Properties {
-			properties[propName] = convertSchemaType(propRef.Value)
+// typeName strips characters that are not valid in a TypeScript identifier
+func typeName(name string) string {
+	var b strings.Builder
+	upperNext := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '$':
+			if upperNext {
+				r = []rune(strings.ToUpper(string(r)))[0]
+				upperNext = false
+			}
+			b.WriteRune(r)
+		default:
+			upperNext = b.Len() > 0
 		}
 	}
+	result := b.String()
+	if result == "" || (result[0] >= '0' && result[0] <= '9') {
+		result = "Schema" + result
+	}
+	return result
+}
 
-	return Schema{
-		Name:       originalName,
-		Type:       getSchemaType(schema.Type),
-		Properties: properties,
-		Required:   schema.Required,
-		Namespace:  namespace,
+// resolveRef returns the generated schema name for a component reference,
+// or an empty string if the reference does not point at a known component
+func (c *schemaConverter) resolveRef(ref string) string {
+	if ref == "" {
+		return ""
 	}
+	idx := strings.LastIndex(ref, "#/components/schemas/")
+	if idx < 0 {
+		return ""
+	}
+	key := ref[idx+len("#/components/schemas/"):]
+	return c.names[key]
 }
 
-func convertSchemaType(schema *openapi3.Schema) interface{} {
-	schemaType := getSchemaType(schema.Type)
-	
-	switch schemaType {
-	case "string":
-		return "string"
-	case "integer", "number":
-		return "number"
-	case "boolean":
-		return "boolean"
+func parseSchema(key string, schemaRef *openapi3.SchemaRef, conv *schemaConverter) Schema {
+	namespace, _ := splitSchemaName(key)
+	name := conv.names[key]
+
+	// A component that is itself a $ref is an alias of the referenced schema
+	var def *SchemaDef
+	if target := conv.resolveRef(schemaRef.Ref); target != "" && target != name {
+		def = &SchemaDef{Ref: target}
+	} else {
+		def = conv.convertSchema(schemaRef.Value)
+	}
+
+	schema := Schema{
+		Name:      name,
+		Namespace: namespace,
+		Def:       def,
+	}
+	if schemaRef.Value != nil {
+		schema.Type = getSchemaType(schemaRef.Value.Type)
+		schema.Required = schemaRef.Value.Required
+		schema.Description = schemaRef.Value.Description
+	}
+	return schema
+}
+
+// convertSchemaType converts a schema reference, keeping references to
+// component schemas by name
+func (c *schemaConverter) convertSchemaType(schemaRef *openapi3.SchemaRef) *SchemaDef {
+	if schemaRef == nil {
+		return &SchemaDef{}
+	}
+	if name := c.resolveRef(schemaRef.Ref); name != "" {
+		return &SchemaDef{Ref: name}
+	}
+	return c.convertSchema(schemaRef.Value)
+}
+
+// convertSchema converts an inline schema into a SchemaDef
+func (c *schemaConverter) convertSchema(schema *openapi3.Schema) *SchemaDef {
+	if schema == nil {
+		return &SchemaDef{}
+	}
+	// Guard against inline cycles through references we could not name
+	if c.visiting[schema] {
+		return &SchemaDef{}
+	}
+	c.visiting[schema] = true
+	defer delete(c.visiting, schema)
+
+	def := &SchemaDef{
+		Type:             getSchemaType(schema.Type),
+		Format:           schema.Format,
+		Description:      schema.Description,
+		Example:          schema.Example,
+		Nullable:         schema.Nullable || (schema.Type != nil && schema.Type.Includes("null") && len(*schema.Type) > 1),
+		Enum:             schema.Enum,
+		Default:          schema.Default,
+		Minimum:          schema.Min,
+		Maximum:          schema.Max,
+		ExclusiveMinimum: schema.ExclusiveMin,
+		ExclusiveMaximum: schema.ExclusiveMax,
+		MaxLength:        schema.MaxLength,
+		MaxItems:         schema.MaxItems,
+		Pattern:          schema.Pattern,
+	}
+	if schema.MinLength > 0 {
+		minLength := schema.MinLength
+		def.MinLength = &minLength
+	}
+	if schema.MinItems > 0 {
+		minItems := schema.MinItems
+		def.MinItems = &minItems
+	}
+
+	// Infer the type from the keywords when it is not declared
+	if def.Type == "" {
+		switch {
+		case len(schema.Properties) > 0 || schema.AdditionalProperties.Schema != nil:
+			def.Type = "object"
+		case schema.Items != nil:
+			def.Type = "array"
+		}
+	}
+
+	def.AllOf = c.convertSchemaRefs(schema.AllOf)
+	def.OneOf = c.convertSchemaRefs(schema.OneOf)
+	def.AnyOf = c.convertSchemaRefs(schema.AnyOf)
+	if schema.Discriminator != nil && (len(def.OneOf) > 0 || len(def.AnyOf) > 0) {
+		def.Discriminator = c.convertDiscriminator(schema.Discriminator)
+	}
+
+	switch def.Type {
 	case "array":
 		if schema.Items != nil {
-			return map[string]interface{}{
-				"type": "array",
-				"items": convertSchemaType(schema.Items.Value),
-			}
+			def.Items = c.convertSchemaType(schema.Items)
 		}
-		return "array"
 	case "object":
-		return "object"
-	case "null":
-		// Handle null type from OpenAPI 3.1.0
-		return "null"
-	default:
-		return "any"
+		required := make(map[string]bool, len(schema.Required))
+		for _, name := range schema.Required {
+			required[name] = true
+		}
+		for _, propName := range sortedKeys(schema.Properties) {
+			def.Properties = append(def.Properties, Property{
+				Name:     propName,
+				Required: required[propName],
+				Def:      c.convertSchemaType(schema.Properties[propName]),
+			})
+		}
+		if schema.AdditionalProperties.Schema != nil {
+			def.AdditionalProperties = c.convertSchemaType(schema.AdditionalProperties.Schema)
+		} else if schema.AdditionalProperties.Has != nil && !*schema.AdditionalProperties.Has {
+			def.Strict = true
+		}
+	}
+
+	return def
+}
+
+func (c *schemaConverter) convertSchemaRefs(refs openapi3.SchemaRefs) []*SchemaDef {
+	if len(refs) == 0 {
+		return nil
+	}
+	defs := make([]*SchemaDef, 0, len(refs))
+	for _, ref := range refs {
+		defs = append(defs, c.convertSchemaType(ref))
+	}
+	return defs
+}
+
+// convertDiscriminator resolves the discriminator mapping targets, which may
+// be written either as references or as bare component names
+func (c *schemaConverter) convertDiscriminator(d *openapi3.Discriminator) *Discriminator {
+	result := &Discriminator{
+		PropertyName: d.PropertyName,
+		Mapping:      make(map[string]string, len(d.Mapping)),
+	}
+	for value, target := range d.Mapping {
+		name := c.resolveRef(target)
+		if name == "" {
+			name = c.names[target]
+		}
+		if name != "" {
+			result.Mapping[value] = name
+		}
 	}
+	return result
 }
 
-func extractOperations(path string, pathItem *openapi3.PathItem) []Operation {
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func extractOperations(path string, pathItem *openapi3.PathItem, conv *schemaConverter) []Operation {
 	operations := []Operation{}
 
-	methods := map[string]*openapi3.Operation{
-		"GET":    pathItem.Get,
-		"POST":   pathItem.Post,
-		"PUT":    pathItem.Put,
-		"DELETE": pathItem.Delete,
-		"PATCH":  pathItem.Patch,
+	// Methods are listed in a fixed order to keep the output stable
+	methods := []struct {
+		name      string
+		operation *openapi3.Operation
+	}{
+		{"GET", pathItem.Get},
+		{"POST", pathItem.Post},
+		{"PUT", pathItem.Put},
+		{"PATCH", pathItem.Patch},
+		{"DELETE", pathItem.Delete},
+		{"HEAD", pathItem.Head},
+		{"OPTIONS", pathItem.Options},
+		{"TRACE", pathItem.Trace},
 	}
 
-	for method, operation := range methods {
+	for _, m := range methods {
+		method, operation := m.name, m.operation
 		if operation == nil {
 			continue
 		}
@@ -229,7 +555,14 @@ func extractOperations(path string, pathItem *openapi3.PathItem) []Operation {
 			Summary:     operation.Summary,
 			Description: operation.Description,
 			Tags:        operation.Tags,
-			Parameters:  extractParameters(operation.Parameters),
+			Parameters:  extractParameters(mergeParameters(pathItem.Parameters, operation.Parameters), conv),
+			Security:    securityRequirements(operation.Security),
+		}
+		applyPaginationExtension(&op, operation)
+		op.Invalidates = invalidatesOf(operation)
+		if op.ID == "" {
+			op.ID = synthesizeOperationID(method, path)
+			op.SynthesizedID = true
 		}
 
 		// Extract namespace and entity from tags or operationId
@@ -251,7 +584,12 @@ func extractOperations(path string, pathItem *openapi3.PathItem) []Operation {
 
 		// Extract request body
 		if operation.RequestBody != nil {
-			op.RequestBody = extractRequestBody(operation.RequestBody)
+			op.RequestBody = extractRequestBody(operation.RequestBody, conv)
+		}
+
+		// Extract responses per status code
+		if operation.Responses != nil {
+			op.Responses = extractResponses(operation.Responses, conv)
 		}
 
 		operations = append(operations, op)
@@ -260,7 +598,77 @@ func extractOperations(path string, pathItem *openapi3.PathItem) []Operation {
 	return operations
 }
 
-func extractParameters(params openapi3.Parameters) []Parameter {
+// mergeParameters applies the operation parameters over the ones shared by
+// the path item. An operation parameter with the same name and location
+// replaces the shared one in place; the others follow in declaration order.
+func mergeParameters(shared, own openapi3.Parameters) openapi3.Parameters {
+	if len(shared) == 0 {
+		return own
+	}
+	overrides := make(map[string]*openapi3.ParameterRef, len(own))
+	for _, paramRef := range own {
+		if paramRef.Value != nil {
+			overrides[paramRef.Value.In+" "+paramRef.Value.Name] = paramRef
+		}
+	}
+	merged := make(openapi3.Parameters, 0, len(shared)+len(own))
+	used := make(map[*openapi3.ParameterRef]bool, len(own))
+	for _, paramRef := range shared {
+		if paramRef.Value == nil {
+			continue
+		}
+		if override, ok := overrides[paramRef.Value.In+" "+paramRef.Value.Name]; ok {
+			paramRef = override
+			used[override] = true
+		}
+		merged = append(merged, paramRef)
+	}
+	for _, paramRef := range own {
+		if !used[paramRef] {
+			merged = append(merged, paramRef)
+		}
+	}
+	return merged
+}
+
+// synthesizeOperationID derives an id for an operation without operationId
+// from its method and path, e.g. GET /users/{id}/posts -> getUsersByIdPosts
+func synthesizeOperationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			b.WriteString("By")
+			segment = strings.Trim(segment, "{}")
+		}
+		b.WriteString(pascalWords(segment))
+	}
+	if b.Len() == len(method) {
+		b.WriteString("Root")
+	}
+	return b.String()
+}
+
+// pascalWords joins the alphanumeric words of s in PascalCase
+func pascalWords(s string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			if upperNext {
+				r = []rune(strings.ToUpper(string(r)))[0]
+				upperNext = false
+			}
+			b.WriteRune(r)
+		default:
+			upperNext = true
+		}
+	}
+	return b.String()
+}
+
+func extractParameters(params openapi3.Parameters, conv *schemaConverter) []Parameter {
 	parameters := []Parameter{}
 
 	for _, paramRef := range params {
@@ -271,9 +679,14 @@ func extractParameters(params openapi3.Parameters) []Parameter {
 				Required: paramRef.Value.Required,
 				Type:     getParameterType(paramRef.Value.In),
 			}
+			if method, err := paramRef.Value.SerializationMethod(); err == nil {
+				param.Style = method.Style
+				param.Explode = method.Explode
+			}
 
 			if paramRef.Value.Schema != nil && paramRef.Value.Schema.Value != nil {
 				param.Schema = getSchemaReference(paramRef.Value.Schema.Value)
+				param.Def = conv.convertSchemaType(paramRef.Value.Schema)
 			}
 
 			parameters = append(parameters, param)
@@ -283,13 +696,18 @@ func extractParameters(params openapi3.Parameters) []Parameter {
 	return parameters
 }
 
-func extractRequestBody(requestBody *openapi3.RequestBodyRef) *RequestBody {
+func extractRequestBody(requestBody *openapi3.RequestBodyRef, conv *schemaConverter) *RequestBody {
 	if requestBody.Value == nil {
 		return nil
 	}
 
 	rb := &RequestBody{
 		Required: requestBody.Value.Required,
+		Content:  mediaSchemas(requestBody.Value.Content, conv),
+	}
+	rb.ContentType = primaryMediaType(rb.Content)
+	if rb.ContentType != "" {
+		rb.Def = rb.Content[rb.ContentType]
 	}
 
 	// Extract schema from content (assuming JSON)
@@ -312,7 +730,7 @@ func extractRequestBody(requestBody *openapi3.RequestBodyRef) *RequestBody {
 	return rb
 }
 
-func extractResponses(responses *openapi3.Responses) map[string]Response {
+func extractResponses(responses *openapi3.Responses, conv *schemaConverter) map[string]Response {
 	result := make(map[string]Response)
 
 	for status, responseRef := range responses.Map() {
@@ -321,6 +739,14 @@ func extractResponses(responses *openapi3.Responses) map[string]Response {
 				Description: derefString(responseRef.Value.Description),
 			}
 
+			// Keep every media type, and the JSON schema as the default
+			response.Content = mediaSchemas(responseRef.Value.Content, conv)
+			response.ContentType = primaryMediaType(response.Content)
+			response.Def = jsonContent(response.Content)
+			if mediaType := jsonMediaType(responseRef.Value.Content); mediaType != "" {
+				response.Examples = mediaExamples(responseRef.Value.Content[mediaType])
+			}
+
 			// Extract schema from content (assuming JSON)
 			if content := responseRef.Value.Content["application/json"]; content != nil {
 				if content.Schema != nil {
@@ -345,6 +771,77 @@ func extractResponses(responses *openapi3.Responses) map[string]Response {
 	return result
 }
 
+// jsonContent returns the schema of the JSON media type, preferring
+// application/json over vendor types such as application/problem+json
+func jsonContent(content map[string]*SchemaDef) *SchemaDef {
+	if mediaType := jsonMediaType(content); mediaType != "" {
+		return content[mediaType]
+	}
+	return nil
+}
+
+// mediaSchemas converts the schema of every media type of content; media
+// types without a schema map to nil
+func mediaSchemas(content openapi3.Content, conv *schemaConverter) map[string]*SchemaDef {
+	if len(content) == 0 {
+		return nil
+	}
+	schemas := make(map[string]*SchemaDef, len(content))
+	for mediaType, media := range content {
+		var def *SchemaDef
+		if media != nil && media.Schema != nil {
+			def = conv.convertSchemaType(media.Schema)
+		}
+		schemas[mediaType] = def
+	}
+	return schemas
+}
+
+// primaryMediaType picks the media type a generated client uses: JSON when
+// declared, otherwise the first in name order
+func primaryMediaType(content map[string]*SchemaDef) string {
+	if mediaType := jsonMediaType(content); mediaType != "" {
+		return mediaType
+	}
+	if keys := sortedKeys(content); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// jsonMediaType returns the JSON media type among the keys of content, or ""
+func jsonMediaType[V any](content map[string]V) string {
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	for _, mediaType := range sortedKeys(content) {
+		if strings.HasSuffix(mediaType, "+json") || strings.HasPrefix(mediaType, "application/json") {
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// mediaExamples collects the example values of a media type
+func mediaExamples(content *openapi3.MediaType) map[string]interface{} {
+	if content == nil {
+		return nil
+	}
+	examples := make(map[string]interface{})
+	if content.Example != nil {
+		examples["default"] = content.Example
+	}
+	for name, example := range content.Examples {
+		if example != nil && example.Value != nil && example.Value.Value != nil {
+			examples[name] = example.Value.Value
+		}
+	}
+	if len(examples) == 0 {
+		return nil
+	}
+	return examples
+}
+
 func getParameterType(in string) string {
 	switch in {
 	case "query":
@@ -0,0 +1,713 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// writeSpec writes an inline spec to a temp file and returns its path
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+	return path
+}
+
+func findSchema(spec *ParsedSpec, name string) *Schema {
+	for i := range spec.Schemas {
+		if spec.Schemas[i].Name == name {
+			return &spec.Schemas[i]
+		}
+	}
+	return nil
+}
+
+func findProperty(def *SchemaDef, name string) *Property {
+	for i := range def.Properties {
+		if def.Properties[i].Name == name {
+			return &def.Properties[i]
+		}
+	}
+	return nil
+}
+
+const refSpec = `openapi: 3.0.3
+info: {title: test, version: "1"}
+paths: {}
+components:
+  schemas:
+    nodes_Node:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string, format: uuid}
+        kind: {type: string, enum: [leaf, branch], default: leaf}
+        weight: {type: integer, minimum: 0, maximum: 100}
+        children:
+          type: array
+          items: {$ref: "#/components/schemas/nodes_Node"}
+        owner: {$ref: "#/components/schemas/Owner"}
+        meta:
+          type: object
+          properties:
+            labels:
+              type: object
+              additionalProperties: {type: string}
+    Owner:
+      type: object
+      additionalProperties: false
+      properties:
+        email: {type: string, format: email, maxLength: 120}
+    OwnerAlias:
+      $ref: "#/components/schemas/Owner"
+`
+
+func TestParseSchemaKeepsReferences(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, refSpec))
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+
+	node := findSchema(spec, "Node")
+	if node == nil {
+		t.Fatal("schema Node not found")
+	}
+	if node.Namespace != "nodes" {
+		t.Errorf("Node namespace = %q, want nodes", node.Namespace)
+	}
+
+	id := findProperty(node.Def, "id")
+	if id == nil || !id.Required || id.Def.Format != "uuid" {
+		t.Errorf("id property = %+v, want required uuid string", id)
+	}
+
+	children := findProperty(node.Def, "children")
+	if children == nil || children.Def.Type != "array" || children.Def.Items.Ref != "Node" {
+		t.Errorf("children should be an array of Node refs, got %+v", children)
+	}
+
+	owner := findProperty(node.Def, "owner")
+	if owner == nil || owner.Def.Ref != "Owner" {
+		t.Errorf("owner should reference Owner, got %+v", owner)
+	}
+
+	kind := findProperty(node.Def, "kind")
+	if kind == nil || len(kind.Def.Enum) != 2 || kind.Def.Default != "leaf" {
+		t.Errorf("kind should keep enum and default, got %+v", kind)
+	}
+
+	weight := findProperty(node.Def, "weight")
+	if weight == nil || weight.Def.Minimum == nil || *weight.Def.Maximum != 100 {
+		t.Errorf("weight should keep min/max, got %+v", weight)
+	}
+
+	meta := findProperty(node.Def, "meta")
+	labels := findProperty(meta.Def, "labels")
+	if labels == nil || labels.Def.AdditionalProperties == nil || labels.Def.AdditionalProperties.Type != "string" {
+		t.Errorf("meta.labels should be a string map, got %+v", labels)
+	}
+
+	ownerSchema := findSchema(spec, "Owner")
+	if ownerSchema == nil || !ownerSchema.Def.Strict {
+		t.Errorf("Owner should be strict, got %+v", ownerSchema)
+	}
+
+	alias := findSchema(spec, "OwnerAlias")
+	if alias == nil || alias.Def.Ref != "Owner" {
+		t.Errorf("OwnerAlias should alias Owner, got %+v", alias)
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := map[string]string{
+		"User":         "User",
+		"user-profile": "userProfile",
+		"Order.Item":   "OrderItem",
+		"2fa":          "Schema2fa",
+	}
+	for in, want := range tests {
+		if got := typeName(in); got != want {
+			t.Errorf("typeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+const compositionSpec = `openapi: 3.0.3
+info: {title: test, version: "1"}
+paths: {}
+components:
+  schemas:
+    Base:
+      type: object
+      properties:
+        id: {type: string}
+    Cat:
+      allOf:
+        - $ref: "#/components/schemas/Base"
+        - type: object
+          properties:
+            meows: {type: boolean}
+    Dog:
+      allOf:
+        - $ref: "#/components/schemas/Base"
+    Pet:
+      oneOf:
+        - $ref: "#/components/schemas/Cat"
+        - $ref: "#/components/schemas/Dog"
+      discriminator:
+        propertyName: petType
+        mapping:
+          cat: "#/components/schemas/Cat"
+          dog: Dog
+`
+
+func TestParseSchemaComposition(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, compositionSpec))
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+
+	cat := findSchema(spec, "Cat")
+	if cat == nil || len(cat.Def.AllOf) != 2 || cat.Def.AllOf[0].Ref != "Base" {
+		t.Fatalf("Cat should be allOf Base + inline object, got %+v", cat)
+	}
+	if findProperty(cat.Def.AllOf[1], "meows") == nil {
+		t.Errorf("Cat inline member should declare meows")
+	}
+
+	pet := findSchema(spec, "Pet")
+	if pet == nil || len(pet.Def.OneOf) != 2 {
+		t.Fatalf("Pet should be oneOf Cat/Dog, got %+v", pet)
+	}
+	d := pet.Def.Discriminator
+	if d == nil || d.PropertyName != "petType" {
+		t.Fatalf("Pet discriminator = %+v, want petType", d)
+	}
+	if d.Mapping["cat"] != "Cat" || d.Mapping["dog"] != "Dog" {
+		t.Errorf("discriminator mapping = %v, want cat->Cat and dog->Dog", d.Mapping)
+	}
+}
+
+const responsesSpec = `openapi: 3.0.3
+info: {title: test, version: "1"}
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+        "404":
+          description: missing
+          content:
+            application/problem+json:
+              schema: {$ref: "#/components/schemas/Problem"}
+            text/plain:
+              schema: {type: string}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id: {type: string}
+    Problem:
+      type: object
+      properties:
+        title: {type: string}
+`
+
+func TestExtractResponses(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, responsesSpec))
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+	if len(spec.Operations) != 1 {
+		t.Fatalf("expected 1 operation, got %d", len(spec.Operations))
+	}
+
+	responses := spec.Operations[0].Responses
+	if ok := responses["200"]; ok.Def == nil || ok.Def.Ref != "User" {
+		t.Errorf("200 response should reference User, got %+v", ok.Def)
+	}
+
+	notFound := responses["404"]
+	if notFound.Def == nil || notFound.Def.Ref != "Problem" {
+		t.Errorf("404 response should use the problem+json schema, got %+v", notFound.Def)
+	}
+	if def := notFound.Content["text/plain"]; def == nil || def.Type != "string" {
+		t.Errorf("404 response should keep the text/plain schema, got %+v", def)
+	}
+}
+
+const serializationSpec = `openapi: 3.0.3
+info: {title: serialization, version: "1"}
+paths:
+  /items/{ids}:
+    get:
+      operationId: getItems
+      parameters:
+        - {name: ids, in: path, required: true, style: label, explode: true, schema: {type: array, items: {type: string}}}
+        - {name: page, in: query, schema: {type: integer}}
+        - {name: tags, in: query, style: pipeDelimited, explode: false, schema: {type: array, items: {type: string}}}
+        - {name: X-Trace, in: header, schema: {type: string}}
+      responses:
+        "204": {description: empty}
+`
+
+func TestParameterSerialization(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, serializationSpec))
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+
+	want := map[string]struct {
+		style   string
+		explode bool
+	}{
+		"ids":     {"label", true},
+		"page":    {"form", true},
+		"tags":    {"pipeDelimited", false},
+		"X-Trace": {"simple", false},
+	}
+	for _, param := range spec.Operations[0].Parameters {
+		if w := want[param.Name]; param.Style != w.style || param.Explode != w.explode {
+			t.Errorf("%s: got style=%q explode=%v, want style=%q explode=%v", param.Name, param.Style, param.Explode, w.style, w.explode)
+		}
+	}
+}
+
+const swaggerSpec = `swagger: "2.0"
+info: {title: legacy, version: "1"}
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      parameters:
+        - {name: ids, in: query, type: array, items: {type: string}}
+        - {name: tags, in: query, type: array, items: {type: string}, collectionFormat: multi}
+        - {name: codes, in: query, type: array, items: {type: string}, collectionFormat: tsv}
+      responses:
+        "200":
+          description: ok
+          schema:
+            type: array
+            items: {$ref: "#/definitions/User"}
+    post:
+      operationId: createUser
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: "#/definitions/User"}}
+      responses:
+        "201":
+          description: created
+          schema: {$ref: "#/definitions/User"}
+definitions:
+  User:
+    type: object
+    required: [id]
+    properties:
+      id: {type: string}
+      nickname: {type: string, x-nullable: true}
+`
+
+func TestParseSwagger2(t *testing.T) {
+	jsonSpec := `{"swagger": "2.0", "info": {"title": "legacy", "version": "1"}, "host": "api.example.com",
+		"paths": {"/ping": {"get": {"operationId": "ping", "responses": {"200": {"description": "ok"}}}}}}`
+
+	for name, content := range map[string]string{"yaml": swaggerSpec, "json": jsonSpec} {
+		t.Run(name, func(t *testing.T) {
+			spec, err := ParseOpenAPI(writeSpec(t, content), OpenAPIOptions{ErrorsOnly: true})
+			if err != nil {
+				t.Fatalf("ParseOpenAPI() error = %v", err)
+			}
+			if len(spec.Operations) == 0 {
+				t.Fatal("expected operations from the converted spec")
+			}
+		})
+	}
+
+	spec, err := ParseOpenAPI(writeSpec(t, swaggerSpec), OpenAPIOptions{ErrorsOnly: true})
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+	user := findSchema(spec, "User")
+	if user == nil || !findProperty(user.Def, "nickname").Def.Nullable {
+		t.Errorf("User should be converted with a nullable nickname, got %+v", user)
+	}
+	for _, op := range spec.Operations {
+		if op.ID == "createUser" && (op.RequestBody == nil || op.RequestBody.Def.Ref != "User") {
+			t.Errorf("createUser body should reference User, got %+v", op.RequestBody)
+		}
+		if op.ID == "listUsers" && op.Responses["200"].Def.Items.Ref != "User" {
+			t.Errorf("listUsers should return User[], got %+v", op.Responses["200"].Def)
+		}
+	}
+}
+
+func TestSwagger2CollectionFormat(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, conv, err := loadDocument(loader, writeSpec(t, swaggerSpec))
+	if err != nil {
+		t.Fatalf("loadDocument() error = %v", err)
+	}
+	if conv == nil {
+		t.Fatal("Swagger 2.0 input should be reported as converted")
+	}
+
+	params := doc.Paths.Value("/users").Get.Parameters
+	ids := params.GetByInAndName("query", "ids")
+	if ids.Style != openapi3.SerializationForm || ids.Explode == nil || *ids.Explode {
+		t.Errorf("csv array should become form without explode, got style=%q explode=%v", ids.Style, ids.Explode)
+	}
+	tags := params.GetByInAndName("query", "tags")
+	if tags.Explode == nil || !*tags.Explode {
+		t.Errorf("multi array should explode, got %v", tags.Explode)
+	}
+
+	var warned bool
+	for _, w := range conv.Warnings {
+		if strings.Contains(w, "codes") && strings.Contains(w, "tsv") {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("expected a warning for the tsv parameter, got %v", conv.Warnings)
+	}
+}
+
+const pathItemSpec = `openapi: 3.0.3
+info: {title: test, version: "1"}
+paths:
+  /users/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+      - {name: X-Tenant, in: header, schema: {type: string}}
+    get:
+      operationId: getUser
+      parameters:
+        - {name: X-Tenant, in: header, required: true, schema: {type: string}}
+        - {name: fields, in: query, schema: {type: string}}
+      responses:
+        "200": {description: ok}
+    head:
+      responses:
+        "200": {description: ok}
+    options:
+      responses:
+        "204": {description: ok}
+  /users/{id}/posts:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    trace:
+      responses:
+        "200": {description: ok}
+  /users-by-id/posts:
+    trace:
+      responses:
+        "200": {description: ok}
+`
+
+func TestPathItems(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, pathItemSpec))
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+
+	ids := make(map[string]Operation)
+	for _, op := range spec.Operations {
+		ids[op.Method+" "+op.Path] = op
+	}
+	want := map[string]string{
+		"GET /users/{id}":          "getUser",
+		"HEAD /users/{id}":         "headUsersById",
+		"OPTIONS /users/{id}":      "optionsUsersById",
+		"TRACE /users-by-id/posts": "traceUsersByIdPosts", // paths are taken in sorted order
+		"TRACE /users/{id}/posts":  "traceUsersByIdPosts2",
+	}
+	for key, id := range want {
+		op, ok := ids[key]
+		if !ok {
+			t.Errorf("missing operation %s", key)
+			continue
+		}
+		if op.ID != id || op.SynthesizedID != (id != "getUser") {
+			t.Errorf("%s: ID = %q (synthesized %v), want %q", key, op.ID, op.SynthesizedID, id)
+		}
+	}
+
+	var params []string
+	for _, param := range ids["GET /users/{id}"].Parameters {
+		params = append(params, fmt.Sprintf("%s:%s:%v", param.In, param.Name, param.Required))
+	}
+	if got, want := strings.Join(params, ","), "path:id:true,header:X-Tenant:true,query:fields:false"; got != want {
+		t.Errorf("GET parameters = %s, want %s", got, want)
+	}
+	if params := ids["HEAD /users/{id}"].Parameters; len(params) != 2 || params[1].Required {
+		t.Errorf("HEAD should inherit the path-level parameters, got %+v", params)
+	}
+}
+
+func TestSecurity(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, `openapi: 3.0.3
+info: {title: test, version: "1.0"}
+security:
+  - bearerAuth: []
+paths:
+  /me:
+    get:
+      operationId: getMe
+      responses:
+        "200": {description: ok}
+  /health:
+    get:
+      operationId: health
+      security: []
+      responses:
+        "200": {description: ok}
+  /reports:
+    get:
+      operationId: listReports
+      security:
+        - apiKey: []
+        - oauth: [reports:read]
+      responses:
+        "200": {description: ok}
+components:
+  securitySchemes:
+    bearerAuth: {type: http, scheme: bearer, bearerFormat: JWT}
+    apiKey: {type: apiKey, in: query, name: key}
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.example.com/token
+          scopes: {reports:read: read reports, reports:write: write reports}
+`))
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+
+	var names []string
+	for _, scheme := range spec.SecuritySchemes {
+		names = append(names, scheme.Name)
+	}
+	if got := strings.Join(names, ","); got != "apiKey,bearerAuth,oauth" {
+		t.Fatalf("security schemes = %s", got)
+	}
+	if s := spec.SecuritySchemes[0]; s.In != "query" || s.ParamName != "key" {
+		t.Errorf("apiKey scheme = %+v", s)
+	}
+	if s := spec.SecuritySchemes[1]; s.Scheme != "bearer" || s.BearerFormat != "JWT" {
+		t.Errorf("bearer scheme = %+v", s)
+	}
+	if s := spec.SecuritySchemes[2]; strings.Join(s.Scopes, ",") != "reports:read,reports:write" {
+		t.Errorf("oauth scopes = %v", s.Scopes)
+	}
+
+	security := make(map[string]string)
+	for _, op := range spec.Operations {
+		security[op.ID] = fmt.Sprint(op.Security)
+	}
+	want := map[string]string{
+		"getMe":       "[map[bearerAuth:[]]]",
+		"health":      "[]",
+		"listReports": "[map[apiKey:[]] map[oauth:[reports:read]]]",
+	}
+	for id, w := range want {
+		if security[id] != w {
+			t.Errorf("%s security = %s, want %s", id, security[id], w)
+		}
+	}
+}
+
+func TestPagination(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, `openapi: 3.0.3
+info: {title: test, version: "1.0"}
+paths:
+  /events:
+    get:
+      operationId: listEvents
+      parameters:
+        - {name: cursor, in: query, schema: {type: string}}
+        - {name: limit, in: query, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/EventPage"}
+  /users:
+    get:
+      operationId: listUsers
+      parameters:
+        - {name: page, in: query, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {type: string}}
+  /orders:
+    get:
+      operationId: listOrders
+      x-radas-pagination: {style: offset, param: from, items_field: result.orders}
+      parameters:
+        - {name: from, in: query, schema: {type: integer}}
+      responses:
+        "200": {description: ok}
+  /tags:
+    get:
+      operationId: listTags
+      x-radas-pagination: false
+      parameters:
+        - {name: page, in: query, schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {type: string}}
+  /feed:
+    get:
+      operationId: listFeed
+      parameters:
+        - {name: cursor, in: query, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  data: {type: array, items: {type: string}}
+                  meta: {$ref: "#/components/schemas/FeedMeta"}
+  /search:
+    get:
+      operationId: search
+      parameters:
+        - {name: q, in: query, schema: {type: string}}
+      responses:
+        "200": {description: ok}
+components:
+  schemas:
+    EventPage:
+      type: object
+      properties:
+        items: {type: array, items: {type: string}}
+        nextCursor: {type: string, nullable: true}
+    FeedMeta:
+      allOf:
+        - type: object
+          properties:
+            page: {type: object, properties: {next: {type: string}}}
+`), OpenAPIOptions{Pagination: PaginationConventions{
+		OffsetParams: []string{"from"},
+		NextFields:   []string{"nextCursor", "meta.missing", "meta.page.next"},
+	}})
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, op := range spec.Operations {
+		if op.Pagination != nil {
+			got[op.ID] = fmt.Sprintf("%+v", *op.Pagination)
+		}
+	}
+	want := map[string]string{
+		"listEvents": "{Style:cursor Param:cursor SizeParam:limit NextField:nextCursor ItemsField:items TotalField: FirstPage:1}",
+		"listUsers":  "{Style:page Param:page SizeParam: NextField: ItemsField: TotalField: FirstPage:1}",
+		// Dotted conventions reach into nested objects, through refs and allOf
+		"listFeed":   "{Style:cursor Param:cursor SizeParam: NextField:meta.page.next ItemsField:data TotalField: FirstPage:1}",
+		"listOrders": "{Style:offset Param:from SizeParam: NextField: ItemsField:result.orders TotalField: FirstPage:1}",
+	}
+	if len(got) != len(want) {
+		t.Errorf("paginated operations = %v", got)
+	}
+	for id, w := range want {
+		if got[id] != w {
+			t.Errorf("%s pagination = %s, want %s", id, got[id], w)
+		}
+	}
+}
+
+func TestResolveInvalidates(t *testing.T) {
+	spec, err := ParseOpenAPI(writeSpec(t, `openapi: 3.0.3
+info: {title: test, version: "1.0"}
+paths:
+  /projects:
+    get:
+      operationId: listProjects
+      responses:
+        "200": {description: ok}
+    post:
+      operationId: createProject
+      x-radas-invalidates: [listProjects, "get /projects/{id}", archiveProject, "POST /projects", unknown]
+      responses:
+        "201": {description: created}
+  /projects/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      operationId: getProject
+      responses:
+        "200": {description: ok}
+  /projects/{id}/archive:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    post:
+      operationId: archiveProject
+      responses:
+        "204": {description: archived}
+`), OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+	for _, op := range spec.Operations {
+		if op.ID != "createProject" {
+			continue
+		}
+		// Mutations have no query keys, so they are dropped like unknown ids
+		if got := strings.Join(op.Invalidates, ","); got != "listProjects,getProject" {
+			t.Errorf("Invalidates = %s, want listProjects,getProject", got)
+		}
+		return
+	}
+	t.Fatal("createProject not found")
+}
+
+func TestSchemaNameCollision(t *testing.T) {
+	const spec = `openapi: 3.0.3
+info: {title: test, version: "1.0"}
+paths: {}
+components:
+  schemas:
+    users_Error: {type: object}
+    orders_Error: {type: object}
+%s`
+	parsed, err := ParseOpenAPI(writeSpec(t, fmt.Sprintf(spec, "")), OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("ParseOpenAPI() error = %v", err)
+	}
+	if findSchema(parsed, "UsersError") == nil || findSchema(parsed, "OrdersError") == nil {
+		t.Errorf("colliding names should get their namespace in front")
+	}
+
+	// The renamed schema must not meet one declared under that name
+	_, err = ParseOpenAPI(writeSpec(t, fmt.Sprintf(spec, "    UsersError: {type: string}\n")), OpenAPIOptions{})
+	if err == nil || !strings.Contains(err.Error(), `schemas "UsersError" and "users_Error" both generate the type UsersError`) {
+		t.Errorf("expected a collision error, got %v", err)
+	}
+}
@@ -0,0 +1,287 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// paginationExtension declares or disables pagination of an operation
+const paginationExtension = "x-radas-pagination"
+
+// Pagination styles
+const (
+	PaginationCursor = "cursor"
+	PaginationPage   = "page"
+	PaginationOffset = "offset"
+)
+
+// Pagination describes how a list operation is paged
+type Pagination struct {
+	Style      string // cursor, page or offset
+	Param      string // query parameter carrying the cursor, page number or offset
+	SizeParam  string // query parameter carrying the page size, if any
+	NextField  string // response field with the next cursor, dot separated
+	ItemsField string // response field with the items, empty when the response is the array
+	TotalField string // response field with the total number of items, if any
+	FirstPage  int    // number of the first page in the page style
+}
+
+// PaginationConventions lists the parameter and response field names that
+// mark an operation as paginated when it has no x-radas-pagination
+// extension. Field names reach nested fields when dot separated, like
+// meta.nextCursor. Empty lists fall back to DefaultPaginationConventions.
+type PaginationConventions struct {
+	CursorParams []string
+	PageParams   []string
+	OffsetParams []string
+	SizeParams   []string
+	NextFields   []string
+	ItemsFields  []string
+	TotalFields  []string
+}
+
+// DefaultPaginationConventions covers the common cursor, page/limit and
+// offset/limit APIs
+var DefaultPaginationConventions = PaginationConventions{
+	CursorParams: []string{"cursor", "after", "pageToken", "page_token"},
+	PageParams:   []string{"page"},
+	OffsetParams: []string{"offset", "skip"},
+	SizeParams:   []string{"limit", "pageSize", "page_size", "per_page", "size"},
+	NextFields:   []string{"nextCursor", "next_cursor", "nextPageToken", "next_page_token"},
+	ItemsFields:  []string{"items", "data", "results"},
+	TotalFields:  []string{"total", "totalCount", "total_count"},
+}
+
+func (c PaginationConventions) withDefaults() PaginationConventions {
+	d := DefaultPaginationConventions
+	pick := func(names, defaults []string) []string {
+		if len(names) > 0 {
+			return names
+		}
+		return defaults
+	}
+	return PaginationConventions{
+		CursorParams: pick(c.CursorParams, d.CursorParams),
+		PageParams:   pick(c.PageParams, d.PageParams),
+		OffsetParams: pick(c.OffsetParams, d.OffsetParams),
+		SizeParams:   pick(c.SizeParams, d.SizeParams),
+		NextFields:   pick(c.NextFields, d.NextFields),
+		ItemsFields:  pick(c.ItemsFields, d.ItemsFields),
+		TotalFields:  pick(c.TotalFields, d.TotalFields),
+	}
+}
+
+// applyPaginationExtension reads x-radas-pagination, which is either false
+// to turn detection off or an object declaring some or all fields
+func applyPaginationExtension(op *Operation, operation *openapi3.Operation) {
+	switch declared := operation.Extensions[paginationExtension].(type) {
+	case bool:
+		op.paginationOff = !declared
+	case map[string]interface{}:
+		op.Pagination = paginationFromExtension(declared)
+	}
+}
+
+// detectPagination completes the declared pagination of GET operations and
+// detects it from the naming conventions for the others
+func detectPagination(parsed *ParsedSpec, conventions PaginationConventions) {
+	conventions = conventions.withDefaults()
+	schemas := make(map[string]*SchemaDef, len(parsed.Schemas))
+	for _, schema := range parsed.Schemas {
+		schemas[schema.Name] = schema.Def
+	}
+
+	for i := range parsed.Operations {
+		op := &parsed.Operations[i]
+		declared := op.Pagination
+		op.Pagination = nil
+		if op.Method != "GET" || op.paginationOff {
+			continue
+		}
+
+		p := &Pagination{FirstPage: 1}
+		if declared != nil {
+			p = declared
+		}
+		if !completePagination(p, op, responseFields(op, schemas), schemas, conventions) {
+			if declared != nil {
+				fmt.Printf("⚠️ %s on %s %s: cannot tell how it is paginated, set style, param and the response fields\n", paginationExtension, op.Method, op.Path)
+			}
+			continue
+		}
+		op.Pagination = p
+	}
+}
+
+func paginationFromExtension(fields map[string]interface{}) *Pagination {
+	str := func(key string) string {
+		s, _ := fields[key].(string)
+		return s
+	}
+	p := &Pagination{
+		Style:      str("style"),
+		Param:      str("param"),
+		SizeParam:  str("size_param"),
+		NextField:  str("next_field"),
+		ItemsField: str("items_field"),
+		TotalField: str("total_field"),
+		FirstPage:  1,
+	}
+	if first, ok := fields["first_page"].(float64); ok {
+		p.FirstPage = int(first)
+	}
+	return p
+}
+
+// completePagination fills the fields an extension left out from the
+// conventions and reports whether the result describes a usable pagination
+func completePagination(p *Pagination, op *Operation, fields, schemas map[string]*SchemaDef, c PaginationConventions) bool {
+	query := make(map[string]bool)
+	for _, param := range op.Parameters {
+		if param.In == "query" {
+			query[param.Name] = true
+		}
+	}
+	firstParam := func(names []string) string {
+		for _, name := range names {
+			if query[name] {
+				return name
+			}
+		}
+		return ""
+	}
+	firstField := func(names []string, arrays bool) string {
+		for _, name := range names {
+			if def, ok := lookupField(fields, name, schemas); ok && (def != nil && def.Type == "array") == arrays {
+				return name
+			}
+		}
+		return ""
+	}
+
+	if p.Style == "" {
+		switch {
+		case firstParam(c.CursorParams) != "" && firstField(c.NextFields, false) != "":
+			p.Style = PaginationCursor
+		case firstParam(c.PageParams) != "":
+			p.Style = PaginationPage
+		case firstParam(c.OffsetParams) != "":
+			p.Style = PaginationOffset
+		default:
+			return false
+		}
+	}
+	if p.Param == "" {
+		switch p.Style {
+		case PaginationCursor:
+			p.Param = firstParam(c.CursorParams)
+		case PaginationPage:
+			p.Param = firstParam(c.PageParams)
+		case PaginationOffset:
+			p.Param = firstParam(c.OffsetParams)
+		}
+	}
+	if p.SizeParam == "" {
+		p.SizeParam = firstParam(c.SizeParams)
+	}
+	if p.ItemsField == "" {
+		p.ItemsField = firstField(c.ItemsFields, true)
+	}
+	if p.TotalField == "" {
+		p.TotalField = firstField(c.TotalFields, false)
+	}
+	if p.Param == "" || !query[p.Param] {
+		return false
+	}
+
+	switch p.Style {
+	case PaginationCursor:
+		if p.NextField == "" {
+			p.NextField = firstField(c.NextFields, false)
+		}
+		return p.NextField != ""
+	case PaginationPage, PaginationOffset:
+		// Pages end when one comes back empty, so the items must be known
+		return p.ItemsField != "" || fields[""] != nil
+	default:
+		return false
+	}
+}
+
+// responseFields returns the top-level properties of the successful JSON
+// response by name; an array response is keyed by ""
+func responseFields(op *Operation, schemas map[string]*SchemaDef) map[string]*SchemaDef {
+	fields := make(map[string]*SchemaDef)
+	def := op.Responses["default"].Def
+	for _, status := range sortedKeys(op.Responses) {
+		if strings.HasPrefix(status, "2") {
+			def = op.Responses[status].Def
+			break
+		}
+	}
+
+	var collect func(def *SchemaDef, depth int)
+	collect = func(def *SchemaDef, depth int) {
+		if def == nil || depth > 8 {
+			return
+		}
+		if def.Ref != "" {
+			collect(schemas[def.Ref], depth+1)
+			return
+		}
+		if def.Type == "array" {
+			fields[""] = def
+			return
+		}
+		for _, member := range def.AllOf {
+			collect(member, depth+1)
+		}
+		for _, property := range def.Properties {
+			fields[property.Name] = resolveSchema(property.Def, schemas)
+		}
+	}
+	collect(def, 0)
+	return fields
+}
+
+// lookupField finds a dot separated field among the top-level fields of a
+// response, descending into the properties of the objects on the way
+func lookupField(fields map[string]*SchemaDef, name string, schemas map[string]*SchemaDef) (*SchemaDef, bool) {
+	first, rest, nested := strings.Cut(name, ".")
+	def, ok := fields[first]
+	for ok && nested {
+		first, rest, nested = strings.Cut(rest, ".")
+		def, ok = property(def, first, schemas, 0)
+	}
+	return def, ok
+}
+
+// property finds a property of an object schema, including the ones it
+// inherits through allOf
+func property(def *SchemaDef, name string, schemas map[string]*SchemaDef, depth int) (*SchemaDef, bool) {
+	def = resolveSchema(def, schemas)
+	if def == nil || depth > 8 {
+		return nil, false
+	}
+	for _, prop := range def.Properties {
+		if prop.Name == name {
+			return resolveSchema(prop.Def, schemas), true
+		}
+	}
+	for _, member := range def.AllOf {
+		if found, ok := property(member, name, schemas, depth+1); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// resolveSchema follows component references
+func resolveSchema(def *SchemaDef, schemas map[string]*SchemaDef) *SchemaDef {
+	for depth := 0; def != nil && def.Ref != "" && depth < 8; depth++ {
+		def = schemas[def.Ref]
+	}
+	return def
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SecurityScheme is one entry of components.securitySchemes
+type SecurityScheme struct {
+	Name         string // key in components.securitySchemes
+	Type         string // http, apiKey, oauth2 or openIdConnect
+	Scheme       string // lower-cased HTTP auth scheme, e.g. bearer or basic
+	BearerFormat string
+	In           string // where an apiKey is sent: header, query or cookie
+	ParamName    string // header, query or cookie name of an apiKey
+	Description  string
+	Scopes       []string // scopes offered by the flows of an oauth2 scheme
+}
+
+// SecurityRequirement maps the schemes that must all be satisfied to the
+// scopes they need; an operation accepts any one of its requirements
+type SecurityRequirement map[string][]string
+
+// extractSecuritySchemes converts the security schemes in name order
+func extractSecuritySchemes(doc *openapi3.T) []SecurityScheme {
+	if doc.Components == nil {
+		return nil
+	}
+	names := sortedKeys(doc.Components.SecuritySchemes)
+	schemes := make([]SecurityScheme, 0, len(names))
+	for _, name := range names {
+		ref := doc.Components.SecuritySchemes[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		s := ref.Value
+		scheme := SecurityScheme{
+			Name:         name,
+			Type:         s.Type,
+			Scheme:       strings.ToLower(s.Scheme),
+			BearerFormat: s.BearerFormat,
+			In:           s.In,
+			ParamName:    s.Name,
+			Description:  s.Description,
+		}
+		if s.Flows != nil {
+			scopes := make(map[string]bool)
+			for _, flow := range []*openapi3.OAuthFlow{s.Flows.Implicit, s.Flows.Password, s.Flows.ClientCredentials, s.Flows.AuthorizationCode} {
+				if flow == nil {
+					continue
+				}
+				for scope := range flow.Scopes {
+					scopes[scope] = true
+				}
+			}
+			scheme.Scopes = sortedKeys(scopes)
+		}
+		schemes = append(schemes, scheme)
+	}
+	return schemes
+}
+
+// securityRequirements converts declared requirements; nil means none were
+// declared, while an empty slice marks an operation that needs no auth
+func securityRequirements(requirements *openapi3.SecurityRequirements) []SecurityRequirement {
+	if requirements == nil {
+		return nil
+	}
+	result := make([]SecurityRequirement, 0, len(*requirements))
+	for _, requirement := range *requirements {
+		converted := make(SecurityRequirement, len(requirement))
+		for name, scopes := range requirement {
+			if scopes == nil {
+				scopes = []string{}
+			}
+			converted[name] = scopes
+		}
+		result = append(result, converted)
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	oasyaml "github.com/oasdiff/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+// conversion reports a Swagger 2.0 document that was converted to OpenAPI 3
+type conversion struct {
+	Warnings []string // what could not be carried over faithfully
+}
+
+// LoadOpenAPI loads the raw OpenAPI 3 document at specPath without validating
+// it, converting Swagger 2.0 specs like ParseOpenAPI does. It is meant for
+// tools such as the linter that need more than the parsed spec.
+func LoadOpenAPI(specPath string) (*openapi3.T, error) {
+	loader := &openapi3.Loader{Context: context.Background(), IsExternalRefsAllowed: true}
+	doc, _, err := loadDocument(loader, specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+	return doc, nil
+}
+
+// loadDocument loads an OpenAPI 3 document from specPath. Swagger 2.0 specs,
+// in JSON or YAML, are converted on the fly and reported through the
+// returned conversion, which is nil for OpenAPI 3 input.
+func loadDocument(loader *openapi3.Loader, specPath string) (*openapi3.T, *conversion, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isSwagger2(data) {
+		doc, err := loader.LoadFromFile(specPath)
+		return doc, nil, err
+	}
+
+	var doc2 openapi2.T
+	if err := oasyaml.Unmarshal(data, &doc2); err != nil {
+		return nil, nil, fmt.Errorf("failed to read Swagger 2.0 spec: %w", err)
+	}
+	// openapi2conv defaults responses to JSON but drops bodies without a media type
+	if len(doc2.Consumes) == 0 {
+		doc2.Consumes = []string{"application/json"}
+	}
+	doc, err := openapi2conv.ToV3WithLoader(&doc2, loader, &url.URL{Path: specPath})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert Swagger 2.0 spec to OpenAPI 3: %w", err)
+	}
+	return doc, &conversion{Warnings: convertSwagger2Details(&doc2, doc)}, nil
+}
+
+// isSwagger2 reports whether data is a Swagger 2.0 document; YAML decoding
+// covers JSON documents as well
+func isSwagger2(data []byte) bool {
+	var header struct {
+		Swagger string `yaml:"swagger"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return false
+	}
+	return header.Swagger == "2.0"
+}
+
+// convertSwagger2Details fixes up what openapi2conv leaves out and returns a
+// warning for everything that still has no OpenAPI 3 equivalent
+func convertSwagger2Details(doc2 *openapi2.T, doc *openapi3.T) []string {
+	var warnings []string
+	if doc2.Host == "" {
+		warnings = append(warnings, "no host declared, so no server URL was derived; set base_url for the contract")
+	}
+
+	for _, name := range sortedKeys(doc2.Parameters) {
+		param := doc2.Parameters[name]
+		if target, ok := doc.Components.Parameters[name]; ok && target.Value != nil {
+			if w := applyCollectionFormat(param, target.Value); w != "" {
+				warnings = append(warnings, fmt.Sprintf("parameter %s: %s", name, w))
+			}
+		}
+	}
+
+	for _, path := range sortedKeys(doc2.Paths) {
+		item2 := doc2.Paths[path]
+		item := doc.Paths.Value(path)
+		if item == nil {
+			continue
+		}
+		warnings = append(warnings, applyCollectionFormats(path, item2.Parameters, item.Parameters)...)
+
+		ops := item2.Operations()
+		for _, method := range sortedKeys(ops) {
+			if op := item.GetOperation(method); op != nil {
+				where := fmt.Sprintf("%s %s", method, path)
+				warnings = append(warnings, applyCollectionFormats(where, ops[method].Parameters, op.Parameters)...)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(doc2.Definitions) {
+		def := doc2.Definitions[name]
+		if def.Value != nil && def.Value.Discriminator != "" {
+			warnings = append(warnings, fmt.Sprintf(
+				"definition %s: Swagger 2.0 discriminator %q has no mapping or oneOf, subtypes stay separate types",
+				name, def.Value.Discriminator))
+		}
+	}
+	return warnings
+}
+
+func applyCollectionFormats(where string, params2 openapi2.Parameters, params openapi3.Parameters) []string {
+	var warnings []string
+	for _, param2 := range params2 {
+		if param2.Ref != "" {
+			continue
+		}
+		target := params.GetByInAndName(param2.In, param2.Name)
+		if target == nil {
+			continue
+		}
+		if w := applyCollectionFormat(param2, target); w != "" {
+			warnings = append(warnings, fmt.Sprintf("%s parameter %s: %s", where, param2.Name, w))
+		}
+	}
+	return warnings
+}
+
+// applyCollectionFormat carries collectionFormat over to style and explode.
+// Swagger 2.0 defaults arrays to csv while OpenAPI 3 explodes query arrays,
+// so csv has to be spelled out.
+func applyCollectionFormat(param2 *openapi2.Parameter, param *openapi3.Parameter) string {
+	if param2.Type == nil || !param2.Type.Is("array") {
+		return ""
+	}
+	format := param2.CollectionFormat
+	if format == "" {
+		format = "csv"
+	}
+
+	explode := false
+	switch {
+	case format == "csv" && param.In == openapi3.ParameterInQuery:
+		param.Style = openapi3.SerializationForm
+	case format == "csv":
+		param.Style = openapi3.SerializationSimple
+	case format == "multi" && param.In == openapi3.ParameterInQuery:
+		param.Style = openapi3.SerializationForm
+		explode = true
+	case format == "ssv" && param.In == openapi3.ParameterInQuery:
+		param.Style = openapi3.SerializationSpaceDelimited
+	case format == "pipes" && param.In == openapi3.ParameterInQuery:
+		param.Style = openapi3.SerializationPipeDelimited
+	default:
+		return fmt.Sprintf("collectionFormat %q is not supported in %s parameters by OpenAPI 3, using the default serialization", format, param.In)
+	}
+	param.Explode = &explode
+	return ""
+}
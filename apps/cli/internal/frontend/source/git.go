@@ -0,0 +1,130 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// commitPattern matches a full commit id, which never needs revalidation
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// gitSource is a parsed git+ssh://host/repo.git#ref:path location
+type gitSource struct {
+	repo string
+	ref  string
+	file string
+}
+
+func parseGit(location string) (gitSource, error) {
+	repo, fragment, ok := strings.Cut(strings.TrimPrefix(location, "git+"), "#")
+	ref, file, hasFile := strings.Cut(fragment, ":")
+	if !ok || !hasFile || file == "" {
+		return gitSource{}, fmt.Errorf("invalid git spec source %s: expected git+ssh://host/repo.git#ref:path", location)
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+	return gitSource{repo: repo, ref: ref, file: strings.TrimPrefix(file, "/")}, nil
+}
+
+func resolveGit(location string, c *cache, opts Options) (*Resolved, error) {
+	src, err := parseGit(location)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := c.lookup(location)
+	if opts.Offline {
+		return c.offline(location, meta)
+	}
+
+	remoteRef, err := src.remoteRef()
+	if err != nil {
+		if meta != nil {
+			fmt.Printf("⚠️ Failed to reach %s, using cached copy: %v\n", src.repo, err)
+			return c.offline(location, meta)
+		}
+		return nil, err
+	}
+	if meta != nil && meta.RemoteRef == remoteRef {
+		if resolved, err := c.resolved(location, meta); err == nil {
+			return resolved, nil
+		}
+	}
+
+	content, commit, err := src.fetch()
+	if err != nil {
+		return nil, err
+	}
+	return c.store(location, content, specExt(src.file, ""), metadata{Commit: commit, RemoteRef: remoteRef})
+}
+
+// remoteRef asks the remote which object ref currently points to; for an
+// annotated tag this may be the tag rather than the commit
+func (s gitSource) remoteRef() (string, error) {
+	if commitPattern.MatchString(s.ref) {
+		return s.ref, nil
+	}
+
+	out, err := runGit("", "ls-remote", s.repo, s.ref)
+	if err != nil {
+		return "", err
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if sha, name, ok := strings.Cut(line, "\t"); ok {
+			refs[name] = sha
+		}
+	}
+	// Peeled tags point at the commit rather than the tag object
+	for _, name := range []string{"refs/heads/" + s.ref, "refs/tags/" + s.ref + "^{}", "refs/tags/" + s.ref, s.ref} {
+		if sha, ok := refs[name]; ok {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("ref %s not found in %s", s.ref, s.repo)
+}
+
+// fetch downloads the single commit into a scratch repository and reads the
+// spec file from it
+func (s gitSource) fetch() ([]byte, string, error) {
+	dir, err := os.MkdirTemp("", "radas-git-*")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := runGit(dir, "init", "-q"); err != nil {
+		return nil, "", err
+	}
+	if _, err := runGit(dir, "fetch", "-q", "--depth", "1", s.repo, s.ref); err != nil {
+		return nil, "", err
+	}
+	commit, err := runGit(dir, "rev-parse", "FETCH_HEAD^{commit}")
+	if err != nil {
+		return nil, "", err
+	}
+	content, err := runGit(dir, "show", "FETCH_HEAD:"+s.file)
+	if err != nil {
+		return nil, "", fmt.Errorf("spec %s not found at %s: %w", s.file, s.ref, err)
+	}
+	return content, strings.TrimSpace(string(commit)), nil
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	// Never block on a credential prompt
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// LockFileName is written next to radas.yml
+const LockFileName = "radas.lock"
+
+const lockVersion = 1
+
+// Lock records the exact spec content each contract was generated from
+type Lock struct {
+	Version int                  `json:"version"`
+	Specs   map[string]LockEntry `json:"specs"` // keyed by location as written in radas.yml
+}
+
+// LockEntry pins one spec location
+type LockEntry struct {
+	Hash   string `json:"sha256"`
+	ETag   string `json:"etag,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// ReadLock loads a lock file; a missing file yields an empty lock
+func ReadLock(path string) (*Lock, error) {
+	lock := &Lock{Version: lockVersion, Specs: make(map[string]LockEntry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	if lock.Specs == nil {
+		lock.Specs = make(map[string]LockEntry)
+	}
+	return lock, nil
+}
+
+// Record pins the resolved spec under its configured location
+func (l *Lock) Record(location string, r *Resolved) {
+	l.Specs[location] = LockEntry{Hash: r.Hash, ETag: r.ETag, Commit: r.Commit}
+}
+
+// Write saves the lock file, leaving it untouched when nothing changed
+func (l *Lock) Write(path string) error {
+	l.Version = lockVersion
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+// Package source resolves OpenAPI spec locations to local files, fetching
+// remote specs into a content-addressed cache
+package source
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Options controls how remote specs are fetched and cached
+type Options struct {
+	CacheDir string       // defaults to ~/.config/radas/cache
+	Offline  bool         // only use specs that are already cached
+	Client   *http.Client // defaults to a client with a 30s timeout
+}
+
+// Resolved is a spec location resolved to a file that can be parsed
+type Resolved struct {
+	Location string // location as written in the configuration
+	Path     string // local file holding the spec content
+	Hash     string // sha256 of the spec content
+	ETag     string // HTTP entity tag, for http(s) sources
+	Commit   string // resolved commit, for git sources
+	Remote   bool
+	Cached   bool // content was served from the cache without downloading
+}
+
+// metadata is stored per location next to the cached content
+type metadata struct {
+	Location     string    `json:"location"`
+	Hash         string    `json:"sha256"`
+	Ext          string    `json:"ext"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Commit       string    `json:"commit,omitempty"`
+	RemoteRef    string    `json:"remote_ref,omitempty"` // id the git remote advertised for the ref
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// IsRemote reports whether location refers to an http(s) or git+ssh source
+func IsRemote(location string) bool {
+	return isHTTP(location) || isGit(location)
+}
+
+func isHTTP(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+func isGit(location string) bool {
+	return strings.HasPrefix(location, "git+ssh://")
+}
+
+// DefaultCacheDir returns ~/.config/radas/cache
+func DefaultCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "radas", "cache"), nil
+}
+
+// Resolve returns a local file for location. Local paths are hashed in place;
+// remote sources are revalidated against the cache and downloaded on change.
+func Resolve(location string, opts Options) (*Resolved, error) {
+	if !IsRemote(location) {
+		hash, err := hashFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API spec: %w", err)
+		}
+		return &Resolved{Location: location, Path: location, Hash: hash}, nil
+	}
+
+	if opts.CacheDir == "" {
+		dir, err := DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		opts.CacheDir = dir
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	c := &cache{dir: opts.CacheDir}
+	if isGit(location) {
+		return resolveGit(location, c, opts)
+	}
+	return resolveHTTP(location, c, opts)
+}
+
+func resolveHTTP(location string, c *cache, opts Options) (*Resolved, error) {
+	meta := c.lookup(location)
+	if opts.Offline {
+		return c.offline(location, meta)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec URL %s: %w", location, err)
+	}
+	if meta != nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		if meta != nil {
+			fmt.Printf("⚠️ Failed to fetch %s, using cached copy: %v\n", location, err)
+			return c.offline(location, meta)
+		}
+		return nil, fmt.Errorf("failed to fetch API spec %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && meta != nil:
+		resolved, err := c.resolved(location, meta)
+		if err == nil {
+			return resolved, nil
+		}
+		// The cached content is gone or corrupt, forget it and fetch unconditionally
+		if err := os.Remove(c.indexPath(location)); err != nil {
+			return nil, fmt.Errorf("failed to reset cache for %s: %w", location, err)
+		}
+		return resolveHTTP(location, c, opts)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch API spec %s: %s", location, resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API spec %s: %w", location, err)
+	}
+	return c.store(location, content, specExt(req.URL.Path, resp.Header.Get("Content-Type")), metadata{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+}
+
+// specExt keeps a yaml or json extension on cached files so they stay
+// readable and are parsed the same way as the original
+func specExt(urlPath, contentType string) string {
+	switch ext := strings.ToLower(path.Ext(urlPath)); ext {
+	case ".json", ".yaml", ".yml":
+		return ext
+	}
+	if strings.Contains(contentType, "json") {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+// cache stores spec content under objects/<sha256><ext> and per-location
+// metadata under index/<sha256 of location>.json
+type cache struct {
+	dir string
+}
+
+func (c *cache) objectPath(hash, ext string) string {
+	return filepath.Join(c.dir, "objects", hash+ext)
+}
+
+func (c *cache) indexPath(location string) string {
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(c.dir, "index", hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *cache) lookup(location string) *metadata {
+	data, err := os.ReadFile(c.indexPath(location))
+	if err != nil {
+		return nil
+	}
+	var meta metadata
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Location != location {
+		return nil
+	}
+	return &meta
+}
+
+// resolved verifies the cached content still matches its recorded hash
+func (c *cache) resolved(location string, meta *metadata) (*Resolved, error) {
+	objectPath := c.objectPath(meta.Hash, meta.Ext)
+	hash, err := hashFile(objectPath)
+	if err != nil {
+		return nil, err
+	}
+	if hash != meta.Hash {
+		return nil, fmt.Errorf("cached copy of %s is corrupt", location)
+	}
+	return &Resolved{
+		Location: location,
+		Path:     objectPath,
+		Hash:     meta.Hash,
+		ETag:     meta.ETag,
+		Commit:   meta.Commit,
+		Remote:   true,
+		Cached:   true,
+	}, nil
+}
+
+func (c *cache) offline(location string, meta *metadata) (*Resolved, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("API spec %s is not cached, run once without --offline", location)
+	}
+	resolved, err := c.resolved(location, meta)
+	if err != nil {
+		return nil, fmt.Errorf("%w, run once without --offline", err)
+	}
+	return resolved, nil
+}
+
+// store writes content to the cache unless an intact identical copy is already there
+func (c *cache) store(location string, content []byte, ext string, meta metadata) (*Resolved, error) {
+	sum := sha256.Sum256(content)
+	meta.Location = location
+	meta.Hash = hex.EncodeToString(sum[:])
+	meta.Ext = ext
+	meta.FetchedAt = time.Now().UTC()
+
+	objectPath := c.objectPath(meta.Hash, ext)
+	if existing, err := hashFile(objectPath); err != nil || existing != meta.Hash {
+		if err := writeAtomic(objectPath, content); err != nil {
+			return nil, fmt.Errorf("failed to cache API spec: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeAtomic(c.indexPath(location), data); err != nil {
+		return nil, fmt.Errorf("failed to cache API spec metadata: %w", err)
+	}
+
+	return &Resolved{
+		Location: location,
+		Path:     objectPath,
+		Hash:     meta.Hash,
+		ETag:     meta.ETag,
+		Commit:   meta.Commit,
+		Remote:   true,
+	}, nil
+}
+
+// writeAtomic writes through a temporary file so concurrent runs never see
+// partial content
+func writeAtomic(filePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func hashFile(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,153 @@
+package source
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// specServer serves a mutable spec with an ETag derived from its version
+type specServer struct {
+	version    atomic.Int32
+	downloads  atomic.Int32
+	notChanged atomic.Int32
+}
+
+func (s *specServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	etag := fmt.Sprintf(`"v%d"`, s.version.Load())
+	if r.Header.Get("If-None-Match") == etag {
+		s.notChanged.Add(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.downloads.Add(1)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"openapi": "3.0.3", "info": {"title": "t", "version": "%d"}, "paths": {}}`, s.version.Load())
+}
+
+func TestResolveHTTPCachesAndRevalidates(t *testing.T) {
+	spec := &specServer{}
+	srv := httptest.NewServer(spec)
+	defer srv.Close()
+
+	opts := Options{CacheDir: t.TempDir(), Client: srv.Client()}
+	location := srv.URL + "/openapi"
+
+	first, err := Resolve(location, opts)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !first.Remote || first.Cached || first.ETag != `"v0"` {
+		t.Errorf("first resolve = %+v, want a fresh download", first)
+	}
+	if filepath.Ext(first.Path) != ".json" {
+		t.Errorf("cached spec %s should keep a .json extension", first.Path)
+	}
+	if filepath.Base(first.Path) != first.Hash+".json" {
+		t.Errorf("cached spec %s should be addressed by its hash %s", first.Path, first.Hash)
+	}
+
+	second, err := Resolve(location, opts)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !second.Cached || second.Hash != first.Hash || spec.notChanged.Load() != 1 {
+		t.Errorf("second resolve = %+v, want a revalidated cache hit", second)
+	}
+
+	spec.version.Store(1)
+	third, err := Resolve(location, opts)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if third.Cached || third.Hash == first.Hash || spec.downloads.Load() != 2 {
+		t.Errorf("third resolve = %+v, want new content after the spec changed", third)
+	}
+}
+
+func TestResolveOffline(t *testing.T) {
+	spec := &specServer{}
+	srv := httptest.NewServer(spec)
+	location := srv.URL + "/openapi.json"
+	cacheDir := t.TempDir()
+
+	if _, err := Resolve(location, Options{CacheDir: cacheDir, Offline: true}); err == nil {
+		t.Fatal("Resolve() offline without a cached copy should fail")
+	}
+
+	online, err := Resolve(location, Options{CacheDir: cacheDir, Client: srv.Client()})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	srv.Close()
+
+	offline, err := Resolve(location, Options{CacheDir: cacheDir, Offline: true})
+	if err != nil {
+		t.Fatalf("Resolve() offline error = %v", err)
+	}
+	if offline.Hash != online.Hash || !offline.Cached {
+		t.Errorf("offline resolve = %+v, want the cached copy %s", offline, online.Hash)
+	}
+
+	// A corrupted cache entry must not be served
+	if err := os.WriteFile(offline.Path, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Resolve(location, Options{CacheDir: cacheDir, Offline: true}); err == nil {
+		t.Error("Resolve() should reject a cached copy that no longer matches its hash")
+	}
+}
+
+func TestResolveLocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte("openapi: 3.0.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := Resolve(path, Options{})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved.Remote || resolved.Path != path || len(resolved.Hash) != 64 {
+		t.Errorf("local resolve = %+v", resolved)
+	}
+}
+
+func TestParseGit(t *testing.T) {
+	src, err := parseGit("git+ssh://git@github.com/acme/contracts.git#v1.2.0:billing/openapi.yaml")
+	if err != nil {
+		t.Fatalf("parseGit() error = %v", err)
+	}
+	want := gitSource{repo: "ssh://git@github.com/acme/contracts.git", ref: "v1.2.0", file: "billing/openapi.yaml"}
+	if src != want {
+		t.Errorf("parseGit() = %+v, want %+v", src, want)
+	}
+
+	if _, err := parseGit("git+ssh://git@github.com/acme/contracts.git#main"); err == nil {
+		t.Error("parseGit() should require a file path")
+	}
+}
+
+func TestLockRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockFileName)
+	lock, err := ReadLock(path)
+	if err != nil {
+		t.Fatalf("ReadLock() on a missing file error = %v", err)
+	}
+	lock.Record("https://example.com/openapi.json", &Resolved{Hash: "abc", ETag: `"v1"`})
+	if err := lock.Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	reread, err := ReadLock(path)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if entry := reread.Specs["https://example.com/openapi.json"]; entry.Hash != "abc" || entry.ETag != `"v1"` {
+		t.Errorf("lock entry = %+v", entry)
+	}
+}
@@ -0,0 +1,236 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// maxDiffLines bounds the Myers search; larger changes are shown as a full replacement
+const maxDiffLines = 20000
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// UnifiedDiff returns a unified diff between two texts, or an empty string
+// when they are equal
+func UnifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	// Line numbers consumed on each side before every op
+	fromPos := make([]int, len(ops)+1)
+	toPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		fromPos[i+1], toPos[i+1] = fromPos[i], toPos[i]
+		if op.kind != '+' {
+			fromPos[i+1]++
+		}
+		if op.kind != '-' {
+			toPos[i+1]++
+		}
+	}
+
+	i := 0
+	for i < len(ops) {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for {
+			for end < len(ops) && ops[end].kind != ' ' {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next < len(ops) && next-end <= 2*diffContext {
+				end = next
+				continue
+			}
+			end += diffContext
+			if end > len(ops) {
+				end = len(ops)
+			}
+			break
+		}
+
+		fromCount := fromPos[end] - fromPos[start]
+		toCount := toPos[end] - toPos[start]
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			hunkRange(fromPos[start], fromCount), hunkRange(toPos[start], toCount)))
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a line edit script using the Myers algorithm after
+// trimming the common prefix and suffix
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// myers computes a shortest edit script with the linear space refinement of
+// the Myers algorithm: the middle snake of the script splits the problem in
+// two smaller ones, so memory stays O(N+M) however far apart the texts are
+func myers(a, b []string) []diffOp {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	if len(a)+len(b) > maxDiffLines {
+		return replaceAll(a, b)
+	}
+	return appendEdits(nil, a, b)
+}
+
+// appendEdits appends the edit script turning a into b to ops
+func appendEdits(ops []diffOp, a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		ops = append(ops, diffOp{' ', a[prefix]})
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		ops = append(ops, replaceAll(a, b)...)
+	default:
+		// Both ends differ, so the script has at least two edits and
+		// both halves around the middle snake are smaller than a and b
+		x, y, u, v := middleSnake(a, b)
+		ops = appendEdits(ops, a[:x], b[:y])
+		for _, line := range a[x:u] {
+			ops = append(ops, diffOp{' ', line})
+		}
+		ops = appendEdits(ops, a[u:], b[v:])
+	}
+
+	for _, line := range common {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// middleSnake runs the forward and the reverse search at once until they
+// overlap and returns the snake where they met, from (x, y) to (u, v), which
+// lies on a shortest edit script
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	offset := n + m + 1
+	// Furthest x reached on every diagonal k = x - y; the reverse search
+	// runs over the reversed texts, where its diagonal k is delta - k
+	forward := make([]int, 2*offset+1)
+	reverse := make([]int, 2*offset+1)
+
+	for d := 0; d <= (n+m+1)/2; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := furthest(forward, offset, k, d)
+			x0, y0 := x, x-k
+			for y := x - k; x < n && y < m && a[x] == b[y]; y++ {
+				x++
+			}
+			forward[offset+k] = x
+			if odd && delta-k >= -(d-1) && delta-k <= d-1 && x+reverse[offset+delta-k] >= n {
+				return x0, y0, x, x - k
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			x := furthest(reverse, offset, k, d)
+			x0, y0 := x, x-k
+			for y := x - k; x < n && y < m && a[n-1-x] == b[m-1-y]; y++ {
+				x++
+			}
+			reverse[offset+k] = x
+			if !odd && delta-k >= -d && delta-k <= d && x+forward[offset+delta-k] >= n {
+				return n - x, m - (x - k), n - x0, m - y0
+			}
+		}
+	}
+	panic("diff: the searches did not meet")
+}
+
+// furthest returns where step d starts on diagonal k, extending the furthest
+// path of the neighbouring diagonals by one insertion or deletion
+func furthest(v []int, offset, k, d int) int {
+	if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+		return v[offset+k+1]
+	}
+	return v[offset+k-1] + 1
+}
+
+func replaceAll(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, diffOp{'-', line})
+	}
+	for _, line := range b {
+		ops = append(ops, diffOp{'+', line})
+	}
+	return ops
+}
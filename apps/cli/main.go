@@ -87,8 +87,8 @@ It includes commands for Frontend (fe), Backend (be), DevOps, and Design teams.`
 	rootCmd.AddCommand(rootcmd.DoctorCmd)
 
 	// Execute
+	// Cobra already reported the error on stderr
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
 		os.Exit(1)
 	}
 }